@@ -53,6 +53,8 @@ const (
 	argCapacitronStyleText = "--capacitron_style_text"
 	// List available speaker ids for the defined multi-speaker model.
 	argListSpeakerIdxs = "--list_speaker_idxs"
+	// List available pre-trained models.
+	argListModels = "--list_models"
 	// List available language ids for the defined multi-lingual model.
 	argListLanguageIdxs = "--list_language_idxs"
 	// [REFERENCE_WAV] Reference wav file to convert in the voice of the speaker_idx or speaker_wav
@@ -71,17 +73,17 @@ const (
 	argVoiceDir = "--voice_dir"
 )
 
-// toArgs converts the TTS configuration to command-line arguments.
-// for the underlying Coqui TTS Python process.
-// TODO: There are other arguments that can be added based on the model type.
-// There's also a lot of room for improvement here, but for now,
-// this function generates the basic arguments needed for synthesis.
-func toArgs(t TTS) []string {
-	// Resolve "auto" device to actual device.
-	device := t.device
-	if device == model.DeviceAuto {
-		device = model.DetectDevice()
+// resolveDevice returns the device to run on, resolving "auto" to the detected device.
+func (t TTS) resolveDevice() model.Device {
+	if t.device == model.DeviceAuto {
+		return model.DetectDevice()
 	}
+	return t.device
+}
+
+// modelArgs returns the arguments that load the configured model on the configured device.
+func modelArgs(t TTS) []string {
+	device := t.resolveDevice()
 
 	args := []string{
 		argDevice, device.String(),
@@ -97,20 +99,31 @@ func toArgs(t TTS) []string {
 	if device == model.DeviceCUDA {
 		args = append(args, argUseCuda, "true")
 	}
+	return args
+}
+
+// toArgs converts the TTS configuration to command-line arguments.
+// for the underlying Coqui TTS Python process.
+// TODO: There are other arguments that can be added based on the model type.
+// There's also a lot of room for improvement here, but for now,
+// this function generates the basic arguments needed for synthesis.
+func toArgs(t TTS) []string {
+	args := modelArgs(t)
 
 	// Handle vocoder if set.
 	if t.vocoder.IsValid() {
 		args = append(args, argVocoderName, t.VocoderName())
 	}
 
-	// TODO: Handle Voice Conversion models.
+	// Voice conversion runs as a separate command, see conversionArgs.
 
 	lang := t.model.CurrentLanguage.String()
 	// We don't know the model type at this point, and we won't know if the model supports voice cloning until we run the command.
 	// So we need to handle the speaker sample and index based on what the user has set.
 	if t.model.IsCustom {
-		if t.speakerSample != "" {
-			args = append(args, argSpeakerWav, t.speakerSample)
+		if len(t.speakerSamples) > 0 {
+			args = append(args, argSpeakerWav)
+			args = append(args, t.speakerSamples...)
 			args = append(args, argLanguageIdx, lang)
 		} else {
 			args = append(args, argSpeakerIdx, t.speakerIdx)
@@ -118,8 +131,9 @@ func toArgs(t TTS) []string {
 	} else {
 		// Handle voice cloning models (XTTS variants, YourTTS).
 		if t.model.SupportsCloning() {
-			if t.speakerSample != "" {
-				args = append(args, argSpeakerWav, t.speakerSample)
+			if len(t.speakerSamples) > 0 {
+				args = append(args, argSpeakerWav)
+				args = append(args, t.speakerSamples...)
 			}
 
 			args = append(args, argLanguageIdx, lang)
@@ -69,7 +69,7 @@ func TestToArgs(t *testing.T) {
 				tts.speakerIdx = "spk1"
 			},
 			expected: []string{
-				argDevice, "mps",
+				argDevice, model.DetectDevice().String(),
 				argModelName, "tts/fr/ljspeech/mock-model",
 				argSpeakerIdx, "spk1",
 			},
@@ -85,7 +85,7 @@ func TestToArgs(t *testing.T) {
 					Model:                MockModel,
 					SupportsVoiceCloning: true,
 				}
-				tts.speakerSample = "/tmp/clone.wav"
+				tts.speakerSamples = []string{"/tmp/clone.wav"}
 				tts.speakerIdx = "spk2"
 			},
 			expected: []string{
@@ -96,6 +96,26 @@ func TestToArgs(t *testing.T) {
 				argSpeakerIdx, "spk2",
 			},
 		},
+		{
+			name: "Voice cloning, several speaker samples",
+			setup: func(tts *TTS) {
+				tts.device = model.DeviceCPU
+				tts.model = model.Identifier{
+					Category:             "tts",
+					CurrentLanguage:      model.English,
+					Dataset:              model.DatasetVCTK,
+					Model:                MockModel,
+					SupportsVoiceCloning: true,
+				}
+				tts.speakerSamples = []string{"/tmp/one.wav", "/tmp/two.wav", "/tmp/three.wav"}
+			},
+			expected: []string{
+				argDevice, "cpu",
+				argModelName, "tts/en/vctk/mock-model",
+				argSpeakerWav, "/tmp/one.wav", "/tmp/two.wav", "/tmp/three.wav",
+				argLanguageIdx, "en",
+			},
+		},
 		{
 			name: "Voice cloning, no speaker sample, not custom",
 			setup: func(tts *TTS) {
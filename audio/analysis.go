@@ -0,0 +1,96 @@
+package audio
+
+import (
+	"math"
+	"slices"
+	"time"
+)
+
+const (
+	// analysisWindow is the length of the windows whose energy is measured for the SNR and silence ratio.
+	analysisWindow = 20 * time.Millisecond
+	// clipLevel is the sample magnitude counted as clipped, just below full scale so 16-bit peaks count too.
+	clipLevel = 0.999
+	// silenceLevel is the level in dBFS below which a window counts as silence.
+	silenceLevel = -50
+	// noiseFraction is the share of the quietest and loudest windows the noise and signal levels are taken from.
+	noiseFraction = 0.1
+)
+
+// Analysis describes the quality of a clip, e.g. to judge whether it makes a good speaker sample.
+type Analysis struct {
+	// Duration is the length of the clip.
+	Duration time.Duration
+	// SampleRate is the number of frames per second.
+	SampleRate int
+	// Channels is the number of channels.
+	Channels int
+	// ClippingPercent is the percentage of samples at full scale, which are likely clipped.
+	ClippingPercent float64
+	// SNR is the estimated signal-to-noise ratio in dB.
+	// It compares the loudest and quietest 10% of the clip, so it assumes there are pauses between words.
+	// +Inf if the pauses are digital silence.
+	SNR float64
+	// SilenceRatio is the share of the clip, from 0 to 1, that is quieter than -50 dBFS.
+	SilenceRatio float64
+}
+
+// Analyze measures the duration, format, clipping, noise and silence of the clip.
+func Analyze(c *Clip) Analysis {
+	a := Analysis{
+		Duration:   c.Duration(),
+		SampleRate: c.SampleRate,
+		Channels:   c.Channels,
+	}
+
+	var clipped int
+	for _, s := range c.Samples {
+		if math.Abs(float64(s)) >= clipLevel {
+			clipped++
+		}
+	}
+	if len(c.Samples) > 0 {
+		a.ClippingPercent = 100 * float64(clipped) / float64(len(c.Samples))
+	}
+
+	powers := c.windowPowers()
+	if len(powers) == 0 {
+		return a
+	}
+
+	silent := dbToGain(silenceLevel)
+	var quiet int
+	for _, p := range powers {
+		if math.Sqrt(p) < silent {
+			quiet++
+		}
+	}
+	a.SilenceRatio = float64(quiet) / float64(len(powers))
+
+	slices.Sort(powers)
+	n := max(1, int(float64(len(powers))*noiseFraction))
+	noise := mean(powers[:n])
+	signal := mean(powers[len(powers)-n:])
+	switch {
+	case signal == 0:
+		a.SNR = 0
+	case noise == 0:
+		a.SNR = math.Inf(1)
+	default:
+		a.SNR = 10 * math.Log10(signal/noise)
+	}
+	return a
+}
+
+// windowPowers returns the mean square of every analysis window of the clip.
+func (c *Clip) windowPowers() []float64 {
+	window := max(1, int(analysisWindow.Seconds()*float64(c.SampleRate)))
+	frames := c.Frames()
+
+	powers := make([]float64, 0, frames/window+1)
+	for start := 0; start < frames; start += window {
+		rms := c.rms(start, min(frames, start+window))
+		powers = append(powers, rms*rms)
+	}
+	return powers
+}
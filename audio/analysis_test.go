@@ -0,0 +1,45 @@
+package audio
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyze(t *testing.T) {
+	// 800ms of speech-like tone with 200ms of quiet background noise.
+	clip, err := Concat(sine(24000, 440, 0.5, 0, 800*time.Millisecond), sine(24000, 3000, 0.001, 0, 200*time.Millisecond))
+	require.NoError(t, err)
+
+	a := Analyze(clip)
+	assert.Equal(t, time.Second, a.Duration)
+	assert.Equal(t, 24000, a.SampleRate)
+	assert.Equal(t, 1, a.Channels)
+	assert.Zero(t, a.ClippingPercent)
+	assert.InDelta(t, 54, a.SNR, 0.5, "SNR should compare the tone to the background noise")
+	assert.InDelta(t, 0.2, a.SilenceRatio, 0.01)
+}
+
+func TestAnalyze_Clipping(t *testing.T) {
+	clip := &Clip{SampleRate: 8000, Channels: 2, Format: PCM16, Samples: []float32{1, -1, 0.5, 0, 0.9995, 0.2, -0.3, 0.1}}
+	assert.InDelta(t, 37.5, Analyze(clip).ClippingPercent, 1e-9)
+}
+
+func TestAnalyze_Silence(t *testing.T) {
+	padded, err := Concat(sine(16000, 440, 0.5, 0, time.Second), Silence(16000, 1, PCM16, time.Second))
+	require.NoError(t, err)
+	a := Analyze(padded)
+	assert.True(t, math.IsInf(a.SNR, 1), "Digital silence has no noise")
+	assert.InDelta(t, 0.5, a.SilenceRatio, 0.01)
+
+	a = Analyze(Silence(16000, 1, PCM16, time.Second))
+	assert.Zero(t, a.SNR)
+	assert.Equal(t, 1.0, a.SilenceRatio)
+
+	a = Analyze(&Clip{SampleRate: 16000, Channels: 1, Format: PCM16})
+	assert.Zero(t, a.Duration)
+	assert.Zero(t, a.SilenceRatio)
+}
@@ -0,0 +1,101 @@
+// Package audio reads, writes and manipulates the WAV audio produced by Coqui TTS, in pure Go.
+package audio
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Clip is decoded audio.
+// Samples are interleaved by channel and scaled to [-1, 1] whatever the format they were stored in.
+type Clip struct {
+	// SampleRate is the number of frames per second.
+	SampleRate int
+	// Channels is the number of interleaved channels.
+	Channels int
+	// Format is the encoding used when the clip is written back out.
+	Format Format
+	// Samples holds the interleaved samples.
+	Samples []float32
+}
+
+// Frames returns the number of frames (one sample per channel) in the clip.
+func (c *Clip) Frames() int {
+	if c.Channels == 0 {
+		return 0
+	}
+	return len(c.Samples) / c.Channels
+}
+
+// Duration returns the length of the clip.
+func (c *Clip) Duration() time.Duration {
+	if c.SampleRate == 0 {
+		return 0
+	}
+	return time.Duration(int64(c.Frames()) * int64(time.Second) / int64(c.SampleRate))
+}
+
+// Validate checks that the clip's properties are usable.
+func (c *Clip) Validate() error {
+	if c.SampleRate <= 0 {
+		return fmt.Errorf("invalid sample rate: %d", c.SampleRate)
+	}
+	if c.Channels <= 0 {
+		return fmt.Errorf("invalid channel count: %d", c.Channels)
+	}
+	if !c.Format.IsValid() {
+		return fmt.Errorf("invalid format: %s", c.Format)
+	}
+	if len(c.Samples)%c.Channels != 0 {
+		return fmt.Errorf("%d samples don't divide into %d channels", len(c.Samples), c.Channels)
+	}
+	return nil
+}
+
+// clone returns a copy of the clip that doesn't share its samples.
+func (c *Clip) clone() *Clip {
+	out := *c
+	out.Samples = append([]float32(nil), c.Samples...)
+	return &out
+}
+
+// Silence returns a silent clip of duration d.
+func Silence(sampleRate, channels int, format Format, d time.Duration) *Clip {
+	frames := int(d.Seconds() * float64(sampleRate))
+	return &Clip{
+		SampleRate: sampleRate,
+		Channels:   channels,
+		Format:     format,
+		Samples:    make([]float32, frames*channels),
+	}
+}
+
+// Concat joins clips one after the other.
+// All clips must have the same sample rate, channel count and format.
+func Concat(clips ...*Clip) (*Clip, error) {
+	if len(clips) == 0 {
+		return nil, errors.New("no clips to concatenate")
+	}
+
+	first := clips[0]
+	total := 0
+	for i, c := range clips {
+		if c.SampleRate != first.SampleRate || c.Channels != first.Channels || c.Format != first.Format {
+			return nil, fmt.Errorf("clip %d (%dHz, %d channels, %s) doesn't match the first clip (%dHz, %d channels, %s)",
+				i, c.SampleRate, c.Channels, c.Format, first.SampleRate, first.Channels, first.Format)
+		}
+		total += len(c.Samples)
+	}
+
+	samples := make([]float32, 0, total)
+	for _, c := range clips {
+		samples = append(samples, c.Samples...)
+	}
+	return &Clip{
+		SampleRate: first.SampleRate,
+		Channels:   first.Channels,
+		Format:     first.Format,
+		Samples:    samples,
+	}, nil
+}
@@ -0,0 +1,48 @@
+package audio
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConcat(t *testing.T) {
+	a := &Clip{SampleRate: 1000, Channels: 1, Format: PCM16, Samples: []float32{0.1, 0.2}}
+	b := &Clip{SampleRate: 1000, Channels: 1, Format: PCM16, Samples: []float32{0.3}}
+
+	joined, err := Concat(a, Silence(1000, 1, PCM16, 2*time.Millisecond), b)
+	require.NoError(t, err)
+	assert.Equal(t, []float32{0.1, 0.2, 0, 0, 0.3}, joined.Samples)
+	assert.Equal(t, 5*time.Millisecond, joined.Duration())
+}
+
+func TestConcat_Mismatch(t *testing.T) {
+	tests := []struct {
+		name string
+		clip *Clip
+	}{
+		{name: "Sample rate", clip: &Clip{SampleRate: 2000, Channels: 1, Format: PCM16}},
+		{name: "Channels", clip: &Clip{SampleRate: 1000, Channels: 2, Format: PCM16}},
+		{name: "Format", clip: &Clip{SampleRate: 1000, Channels: 1, Format: Float32}},
+	}
+
+	first := &Clip{SampleRate: 1000, Channels: 1, Format: PCM16}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Concat(first, tt.clip)
+			assert.Error(t, err)
+		})
+	}
+
+	_, err := Concat()
+	assert.Error(t, err, "Concat needs at least one clip")
+}
+
+func TestSilence(t *testing.T) {
+	clip := Silence(16000, 2, PCM24, 10*time.Millisecond)
+	assert.Equal(t, 160, clip.Frames())
+	assert.Len(t, clip.Samples, 320)
+	assert.Equal(t, PCM24, clip.Format)
+}
@@ -0,0 +1,301 @@
+package audio
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+)
+
+const (
+	// flacBlockSize is the number of frames in every FLAC frame but the last.
+	flacBlockSize = 4096
+	// flacMaxOrder is the highest fixed predictor order FLAC supports.
+	flacMaxOrder = 4
+	// flacMaxRiceParam is the highest Rice parameter expressible with 4 bit parameters, excluding the escape code.
+	flacMaxRiceParam = 14
+)
+
+// EncodeFLAC writes the clip to w as a FLAC file.
+// Each channel is compressed with the best of FLAC's fixed linear predictors and Rice coded residuals.
+// FLAC has no floating point samples, so Float32 clips are stored as 24-bit PCM. G.711 clips are stored as 16-bit PCM.
+func (c *Clip) EncodeFLAC(w io.Writer) error {
+	if err := c.Validate(); err != nil {
+		return err
+	}
+	if c.Channels > 8 {
+		return fmt.Errorf("FLAC supports at most 8 channels, got %d", c.Channels)
+	}
+
+	bits := 24
+	if c.Format.BitsPerSample() <= 16 {
+		bits = 16
+	}
+	samples := c.quantized(bits)
+	frames := c.Frames()
+
+	var buf bytes.Buffer
+	buf.WriteString("fLaC")
+	writeStreamInfo(&buf, c, bits, frames, samples)
+
+	for start, n := 0, 0; start < frames; start, n = start+flacBlockSize, n+1 {
+		end := min(frames, start+flacBlockSize)
+		writeFLACFrame(&buf, samples[start*c.Channels:end*c.Channels], c.Channels, bits, n)
+	}
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// quantized returns the samples as signed integers of the given size.
+func (c *Clip) quantized(bits int) []int64 {
+	scale := math.Ldexp(1, bits-1)
+	out := make([]int64, len(c.Samples))
+	for i, s := range c.Samples {
+		out[i] = int64(quantize(s, scale))
+	}
+	return out
+}
+
+// writeStreamInfo writes the STREAMINFO metadata block, the only one written.
+func writeStreamInfo(buf *bytes.Buffer, c *Clip, bits, frames int, samples []int64) {
+	bw := &bitWriter{}
+	bw.write(1, 1) // Last metadata block.
+	bw.write(0, 7) // STREAMINFO.
+	bw.write(34, 24)
+
+	// Minimum and maximum block size. The last block is allowed to be smaller than the minimum.
+	bw.write(flacBlockSize, 16)
+	bw.write(flacBlockSize, 16)
+	bw.write(0, 24) // Minimum frame size, unknown.
+	bw.write(0, 24) // Maximum frame size, unknown.
+	bw.write(uint64(c.SampleRate), 20)
+	bw.write(uint64(c.Channels-1), 3)
+	bw.write(uint64(bits-1), 5)
+	bw.write(uint64(frames), 36)
+	buf.Write(bw.bytes())
+
+	// The signature is the MD5 of the samples as little-endian signed integers.
+	h := md5.New()
+	sample := make([]byte, 4)
+	for _, s := range samples {
+		binary.LittleEndian.PutUint32(sample, uint32(s))
+		h.Write(sample[:bits/8])
+	}
+	buf.Write(h.Sum(nil))
+}
+
+// writeFLACFrame writes one frame of interleaved samples, each channel as an independent subframe.
+func writeFLACFrame(buf *bytes.Buffer, samples []int64, channels, bits, number int) {
+	blockSize := len(samples) / channels
+
+	bw := &bitWriter{}
+	bw.write(0x3FFE, 14) // Sync code.
+	bw.write(0, 1)       // Reserved.
+	bw.write(0, 1)       // Fixed block size.
+	bw.write(0x7, 4)     // Block size stored at the end of the header, as 16 bits.
+	bw.write(0, 4)       // Sample rate taken from STREAMINFO.
+	bw.write(uint64(channels-1), 4)
+	if bits == 16 {
+		bw.write(0x4, 3)
+	} else {
+		bw.write(0x6, 3)
+	}
+	bw.write(0, 1) // Reserved.
+	bw.writeBytes(utf8Number(uint64(number)))
+	bw.write(uint64(blockSize-1), 16)
+	bw.write(uint64(crc8(bw.bytes())), 8)
+
+	channel := make([]int64, blockSize)
+	for ch := 0; ch < channels; ch++ {
+		for i := range channel {
+			channel[i] = samples[i*channels+ch]
+		}
+		writeSubframe(bw, channel, bits)
+	}
+
+	bw.align()
+	frame := bw.bytes()
+	buf.Write(frame)
+	binary.Write(buf, binary.BigEndian, crc16(frame))
+}
+
+// writeSubframe writes a channel using whichever of the fixed predictors, or verbatim, is smallest.
+func writeSubframe(bw *bitWriter, samples []int64, bits int) {
+	bestOrder, bestParam, bestSize := -1, 0, len(samples)*bits
+	for order := 0; order <= flacMaxOrder && order < len(samples); order++ {
+		param, size := riceParam(fixedResiduals(samples, order))
+		size += order*bits + 6 // Warm-up samples, coding method, partition order and parameter.
+		if size < bestSize {
+			bestOrder, bestParam, bestSize = order, param, size
+		}
+	}
+
+	bw.write(0, 1) // Padding.
+	if bestOrder < 0 {
+		bw.write(0x01, 6) // Verbatim.
+		bw.write(0, 1)    // No wasted bits.
+		for _, s := range samples {
+			bw.writeSigned(s, bits)
+		}
+		return
+	}
+
+	bw.write(uint64(0x08|bestOrder), 6) // Fixed predictor.
+	bw.write(0, 1)                      // No wasted bits.
+	for _, s := range samples[:bestOrder] {
+		bw.writeSigned(s, bits)
+	}
+
+	bw.write(0, 2) // Rice coding with 4 bit parameters.
+	bw.write(0, 4) // A single partition.
+	bw.write(uint64(bestParam), 4)
+	for _, r := range fixedResiduals(samples, bestOrder) {
+		u := zigzag(r)
+		for q := u >> bestParam; q > 0; q-- {
+			bw.write(0, 1)
+		}
+		bw.write(1, 1)
+		bw.write(u&(1<<bestParam-1), bestParam)
+	}
+}
+
+// fixedResiduals returns the prediction errors of FLAC's fixed predictor of the given order.
+func fixedResiduals(s []int64, order int) []int64 {
+	out := make([]int64, 0, len(s)-order)
+	for i := order; i < len(s); i++ {
+		var r int64
+		switch order {
+		case 0:
+			r = s[i]
+		case 1:
+			r = s[i] - s[i-1]
+		case 2:
+			r = s[i] - 2*s[i-1] + s[i-2]
+		case 3:
+			r = s[i] - 3*s[i-1] + 3*s[i-2] - s[i-3]
+		case 4:
+			r = s[i] - 4*s[i-1] + 6*s[i-2] - 4*s[i-3] + s[i-4]
+		}
+		out = append(out, r)
+	}
+	return out
+}
+
+// riceParam returns the Rice parameter that codes the residuals in the fewest bits, and that size.
+func riceParam(residuals []int64) (int, int) {
+	bestParam, bestSize := 0, math.MaxInt
+	for k := 0; k <= flacMaxRiceParam; k++ {
+		size := len(residuals) * (k + 1)
+		for _, r := range residuals {
+			size += int(zigzag(r) >> k)
+		}
+		if size < bestSize {
+			bestParam, bestSize = k, size
+		}
+	}
+	return bestParam, bestSize
+}
+
+// zigzag folds a signed residual into an unsigned value, interleaving positive and negative values.
+func zigzag(r int64) uint64 {
+	return uint64(r<<1) ^ uint64(r>>63)
+}
+
+// utf8Number encodes a frame number the way FLAC does, using the UTF-8 scheme extended to 36 bits.
+func utf8Number(n uint64) []byte {
+	if n < 0x80 {
+		return []byte{byte(n)}
+	}
+
+	// With k continuation bytes of 6 bits each, the leading byte has room for 6-k bits.
+	k := 1
+	for n >= 1<<(5*k+6) {
+		k++
+	}
+	out := make([]byte, k+1)
+	for i := k; i > 0; i-- {
+		out[i] = 0x80 | byte(n&0x3F)
+		n >>= 6
+	}
+	out[0] = byte(0xFF<<(7-k)) | byte(n)
+	return out
+}
+
+// crc8 is the CRC-8 (polynomial x^8 + x^2 + x + 1) protecting FLAC frame headers.
+func crc8(data []byte) uint8 {
+	var crc uint8
+	for _, b := range data {
+		crc ^= b
+		for i := 0; i < 8; i++ {
+			if crc&0x80 != 0 {
+				crc = crc<<1 ^ 0x07
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
+
+// crc16 is the CRC-16 (polynomial x^16 + x^15 + x^2 + 1) protecting whole FLAC frames.
+func crc16(data []byte) uint16 {
+	var crc uint16
+	for _, b := range data {
+		crc ^= uint16(b) << 8
+		for i := 0; i < 8; i++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x8005
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
+
+// bitWriter packs values most significant bit first.
+type bitWriter struct {
+	buf   []byte
+	acc   uint64
+	nbits int
+}
+
+// write appends the low n bits of v.
+func (w *bitWriter) write(v uint64, n int) {
+	for n > 32 {
+		w.write(v>>32, n-32)
+		v, n = v&(1<<32-1), 32
+	}
+	w.acc = w.acc<<n | v&(1<<n-1)
+	w.nbits += n
+	for w.nbits >= 8 {
+		w.nbits -= 8
+		w.buf = append(w.buf, byte(w.acc>>w.nbits))
+	}
+}
+
+// writeSigned appends v as an n bit two's complement integer.
+func (w *bitWriter) writeSigned(v int64, n int) {
+	w.write(uint64(v), n)
+}
+
+func (w *bitWriter) writeBytes(b []byte) {
+	for _, c := range b {
+		w.write(uint64(c), 8)
+	}
+}
+
+// align pads with zero bits up to the next byte boundary.
+func (w *bitWriter) align() {
+	if w.nbits > 0 {
+		w.write(0, 8-w.nbits)
+	}
+}
+
+// bytes returns the complete bytes written so far.
+func (w *bitWriter) bytes() []byte {
+	return w.buf
+}
@@ -0,0 +1,201 @@
+package audio
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/binary"
+	"errors"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// bitReader reads values most significant bit first.
+type bitReader struct {
+	data []byte
+	pos  int
+}
+
+func (r *bitReader) read(n int) uint64 {
+	var v uint64
+	for i := 0; i < n; i++ {
+		bit := r.data[r.pos/8] >> (7 - r.pos%8) & 1
+		v = v<<1 | uint64(bit)
+		r.pos++
+	}
+	return v
+}
+
+func (r *bitReader) readSigned(n int) int64 {
+	return int64(r.read(n)<<(64-n)) >> (64 - n)
+}
+
+// decodeFLAC decodes the subset of FLAC written by EncodeFLAC, checking the CRCs and MD5 signature on the way.
+func decodeFLAC(data []byte) (sampleRate, channels, bits int, samples []int64, err error) {
+	if string(data[:4]) != "fLaC" {
+		return 0, 0, 0, nil, errors.New("missing fLaC marker")
+	}
+	r := &bitReader{data: data, pos: 32}
+	if r.read(1) != 1 || r.read(7) != 0 || r.read(24) != 34 {
+		return 0, 0, 0, nil, errors.New("expected a single STREAMINFO block")
+	}
+	r.read(16 + 16 + 24 + 24)
+	sampleRate = int(r.read(20))
+	channels = int(r.read(3)) + 1
+	bits = int(r.read(5)) + 1
+	total := int(r.read(36))
+	signature := data[r.pos/8 : r.pos/8+16]
+	r.pos += 128
+
+	for len(samples) < total*channels {
+		start := r.pos / 8
+		if r.read(14) != 0x3FFE {
+			return 0, 0, 0, nil, errors.New("lost frame sync")
+		}
+		r.read(2 + 4 + 4 + 4 + 3 + 1)
+		for first := r.read(8); first&0xC0 == 0xC0; first <<= 1 {
+			r.read(8)
+		}
+		blockSize := int(r.read(16)) + 1
+		if uint8(r.read(8)) != crc8(data[start:r.pos/8-1]) {
+			return 0, 0, 0, nil, errors.New("header CRC mismatch")
+		}
+
+		block := make([][]int64, channels)
+		for ch := range block {
+			block[ch] = decodeSubframe(r, blockSize, bits)
+		}
+		for i := 0; i < blockSize; i++ {
+			for ch := range block {
+				samples = append(samples, block[ch][i])
+			}
+		}
+
+		if r.pos%8 != 0 {
+			r.pos += 8 - r.pos%8
+		}
+		if binary.BigEndian.Uint16(data[r.pos/8:]) != crc16(data[start:r.pos/8]) {
+			return 0, 0, 0, nil, errors.New("frame CRC mismatch")
+		}
+		r.pos += 16
+	}
+
+	h := md5.New()
+	sample := make([]byte, 4)
+	for _, s := range samples {
+		binary.LittleEndian.PutUint32(sample, uint32(s))
+		h.Write(sample[:bits/8])
+	}
+	if !bytes.Equal(h.Sum(nil), signature) {
+		return 0, 0, 0, nil, errors.New("MD5 signature mismatch")
+	}
+	return sampleRate, channels, bits, samples, nil
+}
+
+func decodeSubframe(r *bitReader, blockSize, bits int) []int64 {
+	r.read(1)
+	kind := r.read(6)
+	r.read(1)
+
+	out := make([]int64, 0, blockSize)
+	if kind == 0x01 {
+		for i := 0; i < blockSize; i++ {
+			out = append(out, r.readSigned(bits))
+		}
+		return out
+	}
+
+	order := int(kind & 0x07)
+	for i := 0; i < order; i++ {
+		out = append(out, r.readSigned(bits))
+	}
+	r.read(2 + 4)
+	param := int(r.read(4))
+	for len(out) < blockSize {
+		var q uint64
+		for r.read(1) == 0 {
+			q++
+		}
+		u := q<<param | r.read(param)
+		residual := int64(u>>1) ^ -int64(u&1)
+
+		s := out
+		i := len(s)
+		var prediction int64
+		switch order {
+		case 1:
+			prediction = s[i-1]
+		case 2:
+			prediction = 2*s[i-1] - s[i-2]
+		case 3:
+			prediction = 3*s[i-1] - 3*s[i-2] + s[i-3]
+		case 4:
+			prediction = 4*s[i-1] - 6*s[i-2] + 4*s[i-3] - s[i-4]
+		}
+		out = append(out, prediction+residual)
+	}
+	return out
+}
+
+func TestEncodeFLAC(t *testing.T) {
+	noise := &Clip{SampleRate: 16000, Channels: 1, Format: PCM16}
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 5000; i++ {
+		noise.Samples = append(noise.Samples, float32(rng.Float64()*2-1))
+	}
+	stereo, err := Remix(sine(44100, 440, 0.5, 0, 200*time.Millisecond), 2)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		clip *Clip
+		bits int
+	}{
+		{name: "Tone across several frames", clip: sine(24000, 440, 0.8, 0, time.Second), bits: 16},
+		{name: "Noise falls back to verbatim", clip: noise, bits: 16},
+		{name: "Stereo", clip: stereo, bits: 16},
+		{name: "PCM24", clip: &Clip{SampleRate: 48000, Channels: 1, Format: PCM24, Samples: sine(48000, 1000, 0.5, 0, 100*time.Millisecond).Samples}, bits: 24},
+		{name: "Float32 is stored as 24 bits", clip: &Clip{SampleRate: 48000, Channels: 1, Format: Float32, Samples: []float32{0.5, -0.5, 0.25}}, bits: 24},
+		{name: "Shorter than a predictor", clip: &Clip{SampleRate: 8000, Channels: 1, Format: PCM16, Samples: []float32{0.5, -0.5}}, bits: 16},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, tt.clip.EncodeFLAC(&buf))
+
+			sampleRate, channels, bits, samples, err := decodeFLAC(buf.Bytes())
+			require.NoError(t, err)
+			assert.Equal(t, tt.clip.SampleRate, sampleRate)
+			assert.Equal(t, tt.clip.Channels, channels)
+			assert.Equal(t, tt.bits, bits)
+			assert.Equal(t, tt.clip.quantized(tt.bits), samples, "FLAC should be lossless")
+		})
+	}
+}
+
+func TestEncodeFLAC_Compresses(t *testing.T) {
+	clip := sine(24000, 440, 0.5, 0, time.Second)
+
+	var flac, wav bytes.Buffer
+	require.NoError(t, clip.EncodeFLAC(&flac))
+	require.NoError(t, clip.Encode(&wav))
+	assert.Less(t, flac.Len(), wav.Len()/2)
+}
+
+func TestUTF8Number(t *testing.T) {
+	assert.Equal(t, []byte{0x7F}, utf8Number(0x7F))
+	assert.Equal(t, []byte{0xC2, 0x80}, utf8Number(0x80))
+	assert.Equal(t, []byte{0xE0, 0xA0, 0x80}, utf8Number(0x800))
+	assert.Equal(t, []byte{0xF0, 0x90, 0x80, 0x80}, utf8Number(0x10000))
+}
+
+func TestEncodePCM(t *testing.T) {
+	clip := &Clip{SampleRate: 8000, Channels: 1, Format: PCM16, Samples: []float32{0.5, -0.5}}
+	var buf bytes.Buffer
+	require.NoError(t, clip.EncodePCM(&buf))
+	assert.Equal(t, []byte{0x00, 0x40, 0x00, 0xC0}, buf.Bytes())
+}
@@ -0,0 +1,100 @@
+package audio
+
+import "fmt"
+
+// Format is the encoding of the samples in a WAV file.
+type Format int
+
+const (
+	// PCM16 is 16-bit signed integer PCM, the format Coqui TTS writes.
+	PCM16 Format = iota + 1
+	// PCM24 is 24-bit signed integer PCM.
+	PCM24
+	// Float32 is 32-bit IEEE floating point.
+	Float32
+	// MuLaw is 8-bit G.711 μ-law, the telephony codec used in North America and Japan.
+	MuLaw
+	// ALaw is 8-bit G.711 A-law, the telephony codec used in Europe and most other countries.
+	ALaw
+)
+
+// WAV format tags, as stored in the "fmt " chunk.
+const (
+	tagPCM        = 1
+	tagFloat      = 3
+	tagALaw       = 6
+	tagMuLaw      = 7
+	tagExtensible = 0xFFFE
+)
+
+// BitsPerSample returns the size of a single sample in bits.
+func (f Format) BitsPerSample() int {
+	switch f {
+	case PCM16:
+		return 16
+	case PCM24:
+		return 24
+	case Float32:
+		return 32
+	case MuLaw, ALaw:
+		return 8
+	}
+	return 0
+}
+
+// bytesPerSample returns the size of a single sample in bytes.
+func (f Format) bytesPerSample() int {
+	return f.BitsPerSample() / 8
+}
+
+// tag returns the WAV format tag for the format.
+func (f Format) tag() uint16 {
+	switch f {
+	case Float32:
+		return tagFloat
+	case MuLaw:
+		return tagMuLaw
+	case ALaw:
+		return tagALaw
+	}
+	return tagPCM
+}
+
+// IsValid checks if the format is supported.
+func (f Format) IsValid() bool {
+	return f.BitsPerSample() != 0
+}
+
+// String returns a readable name for the format.
+func (f Format) String() string {
+	switch f {
+	case PCM16:
+		return "pcm16"
+	case PCM24:
+		return "pcm24"
+	case Float32:
+		return "float32"
+	case MuLaw:
+		return "mulaw"
+	case ALaw:
+		return "alaw"
+	}
+	return fmt.Sprintf("Format(%d)", int(f))
+}
+
+// formatFor returns the format matching a WAV format tag and sample size.
+func formatFor(tag uint16, bitsPerSample int) (Format, error) {
+	switch {
+	case tag == tagPCM && bitsPerSample == 16:
+		return PCM16, nil
+	case tag == tagPCM && bitsPerSample == 24:
+		return PCM24, nil
+	case tag == tagFloat && bitsPerSample == 32:
+		return Float32, nil
+	case tag == tagMuLaw && bitsPerSample == 8:
+		return MuLaw, nil
+	case tag == tagALaw && bitsPerSample == 8:
+		return ALaw, nil
+	}
+	return 0, fmt.Errorf("unsupported WAV format: tag %d with %d bits per sample", tag, bitsPerSample)
+}
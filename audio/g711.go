@@ -0,0 +1,82 @@
+package audio
+
+const (
+	// muLawBias is added to magnitudes before μ-law encoding so every segment starts on a power of two.
+	muLawBias = 0x84
+	// muLawClip is the largest magnitude μ-law can encode once biased.
+	muLawClip = 32635
+)
+
+// aLawSegmentEnds holds the largest 13-bit magnitude in each A-law segment.
+var aLawSegmentEnds = [8]int32{0x1F, 0x3F, 0x7F, 0xFF, 0x1FF, 0x3FF, 0x7FF, 0xFFF}
+
+// muLawEncode compands a 16-bit sample to G.711 μ-law.
+func muLawEncode(pcm int16) byte {
+	v := int32(pcm)
+	var sign byte
+	if v < 0 {
+		v, sign = -v, 0x80
+	}
+	v = min(v, muLawClip) + muLawBias
+
+	exponent := 7
+	for mask := int32(0x4000); v&mask == 0 && exponent > 0; mask >>= 1 {
+		exponent--
+	}
+	mantissa := byte(v>>(exponent+3)) & 0x0F
+	return ^(sign | byte(exponent)<<4 | mantissa)
+}
+
+// muLawDecode expands a G.711 μ-law sample to 16 bits.
+func muLawDecode(u byte) int16 {
+	u = ^u
+	exponent := (u >> 4) & 0x07
+	v := (int32(u&0x0F)<<3+muLawBias)<<exponent - muLawBias
+	if u&0x80 != 0 {
+		return int16(-v)
+	}
+	return int16(v)
+}
+
+// aLawEncode compands a 16-bit sample to G.711 A-law.
+func aLawEncode(pcm int16) byte {
+	// A-law works on 13-bit magnitudes, with even bits inverted on the wire.
+	v := int32(pcm) >> 3
+	mask := byte(0xD5)
+	if v < 0 {
+		v, mask = -v-1, 0x55
+	}
+
+	segment := 0
+	for segment < len(aLawSegmentEnds) && v > aLawSegmentEnds[segment] {
+		segment++
+	}
+	if segment == len(aLawSegmentEnds) {
+		return 0x7F ^ mask
+	}
+
+	a := byte(segment) << 4
+	if segment < 2 {
+		a |= byte(v>>1) & 0x0F
+	} else {
+		a |= byte(v>>segment) & 0x0F
+	}
+	return a ^ mask
+}
+
+// aLawDecode expands a G.711 A-law sample to 16 bits.
+func aLawDecode(a byte) int16 {
+	a ^= 0x55
+	v := int32(a&0x0F)<<4 + 8
+	switch segment := (a >> 4) & 0x07; segment {
+	case 0:
+	case 1:
+		v += 0x100
+	default:
+		v = (v + 0x100) << (segment - 1)
+	}
+	if a&0x80 != 0 {
+		return int16(v)
+	}
+	return int16(-v)
+}
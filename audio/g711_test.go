@@ -0,0 +1,75 @@
+package audio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestG711_RoundTrip(t *testing.T) {
+	for code := 0; code < 256; code++ {
+		// μ-law has two codes for zero; encoding always picks the positive one.
+		if code != 0x7F {
+			require.Equal(t, byte(code), muLawEncode(muLawDecode(byte(code))), "μ-law code %#x", code)
+		}
+		require.Equal(t, byte(code), aLawEncode(aLawDecode(byte(code))), "A-law code %#x", code)
+	}
+}
+
+func TestG711_Encode(t *testing.T) {
+	tests := []struct {
+		pcm         int16
+		mulaw, alaw byte
+	}{
+		{pcm: 0, mulaw: 0xFF, alaw: 0xD5},
+		{pcm: -1, mulaw: 0x7F, alaw: 0x55},
+		{pcm: 32767, mulaw: 0x80, alaw: 0xAA},
+		{pcm: -32768, mulaw: 0x00, alaw: 0x2A},
+		{pcm: 1000, mulaw: 0xCE, alaw: 0xFA},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.mulaw, muLawEncode(tt.pcm), "μ-law of %d", tt.pcm)
+		assert.Equal(t, tt.alaw, aLawEncode(tt.pcm), "A-law of %d", tt.pcm)
+	}
+}
+
+func TestG711_Error(t *testing.T) {
+	// Companding keeps the error roughly proportional to the signal, within half a quantization step.
+	for pcm := -32768; pcm <= 32767; pcm += 7 {
+		limit := math.Max(16, math.Abs(float64(pcm))/16)
+		require.InDelta(t, pcm, muLawDecode(muLawEncode(int16(pcm))), limit, "μ-law of %d", pcm)
+		require.InDelta(t, pcm, aLawDecode(aLawEncode(int16(pcm))), limit, "A-law of %d", pcm)
+	}
+}
+
+func TestEncode_G711(t *testing.T) {
+	for _, format := range []Format{MuLaw, ALaw} {
+		t.Run(format.String(), func(t *testing.T) {
+			clip := sine(8000, 440, 0.5, 0, 100*time.Millisecond)
+			clip.Format = format
+
+			data, err := clip.Bytes()
+			require.NoError(t, err)
+			assert.Equal(t, format.tag(), binary.LittleEndian.Uint16(data[20:22]))
+			assert.Equal(t, uint32(len(data)-8), binary.LittleEndian.Uint32(data[4:8]))
+			assert.Equal(t, "fact", string(data[38:42]))
+			assert.Equal(t, uint32(clip.Frames()), binary.LittleEndian.Uint32(data[46:50]))
+
+			decoded, err := DecodeBytes(data)
+			require.NoError(t, err)
+			assert.Equal(t, format, decoded.Format)
+			assert.InDeltaSlice(t, clip.Samples, decoded.Samples, 0.02)
+
+			var raw bytes.Buffer
+			require.NoError(t, clip.EncodePCM(&raw))
+			assert.Equal(t, clip.Frames(), raw.Len(), "Raw G.711 is one byte per sample")
+			assert.Equal(t, data[len(data)-raw.Len():], raw.Bytes())
+		})
+	}
+}
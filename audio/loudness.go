@@ -0,0 +1,199 @@
+package audio
+
+import (
+	"fmt"
+	"math"
+)
+
+// LoudnessTarget is the loudness a clip is normalized to.
+type LoudnessTarget struct {
+	// Integrated is the target integrated loudness in LUFS,
+	// e.g. -23 for EBU R128 broadcast or -16 for speech streamed on the web.
+	Integrated float64
+	// TruePeak is the maximum true peak level in dBTP, e.g. -1.
+	TruePeak float64
+}
+
+// Validate checks that the target is achievable.
+func (l LoudnessTarget) Validate() error {
+	if l.Integrated >= 0 || l.Integrated < absoluteGate {
+		return fmt.Errorf("integrated loudness must be between %v and 0 LUFS, got %v", absoluteGate, l.Integrated)
+	}
+	if l.TruePeak > 0 {
+		return fmt.Errorf("true peak must not be above 0 dBTP, got %v", l.TruePeak)
+	}
+	return nil
+}
+
+// Gating parameters from ITU-R BS.1770-4.
+const (
+	// absoluteGate is the loudness in LUFS below which blocks are ignored.
+	absoluteGate = -70.0
+	// relativeGate is how far below the ungated loudness blocks are ignored, in LU.
+	relativeGate = -10.0
+	// blockSize is the length of a gating block in seconds.
+	blockSize = 0.4
+	// blockStep is the distance between the starts of overlapping gating blocks in seconds.
+	blockStep = 0.1
+)
+
+// Loudness returns the integrated loudness of the clip in LUFS, as defined by ITU-R BS.1770-4 and EBU R128.
+// All channels are weighted equally, as they are for mono and stereo.
+// A clip shorter than one 400ms gating block is measured as a single block.
+// Returns -Inf for silence.
+func Loudness(c *Clip) float64 {
+	filtered := kWeight(c)
+	frames := c.Frames()
+
+	size := int(blockSize * float64(c.SampleRate))
+	step := int(blockStep * float64(c.SampleRate))
+	if frames < size {
+		size, step = frames, frames
+	}
+	if size == 0 {
+		return math.Inf(-1)
+	}
+
+	// The mean square of every block, summed over channels.
+	var powers []float64
+	for start := 0; start+size <= frames; start += step {
+		var sum float64
+		for _, s := range filtered[start*c.Channels : (start+size)*c.Channels] {
+			sum += s * s
+		}
+		powers = append(powers, sum/float64(size))
+	}
+
+	gated := gate(powers, absoluteGate)
+	if len(gated) == 0 {
+		return math.Inf(-1)
+	}
+	gated = gate(gated, blockLoudness(mean(gated))+relativeGate)
+	return blockLoudness(mean(gated))
+}
+
+// gate returns the block powers that are louder than threshold LUFS.
+func gate(powers []float64, threshold float64) []float64 {
+	var kept []float64
+	for _, p := range powers {
+		if blockLoudness(p) > threshold {
+			kept = append(kept, p)
+		}
+	}
+	return kept
+}
+
+// blockLoudness converts a mean square power to LUFS.
+func blockLoudness(power float64) float64 {
+	return -0.691 + 10*math.Log10(power)
+}
+
+func mean(values []float64) float64 {
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
+
+// kWeight applies the K-weighting filter of BS.1770 to every channel of the clip.
+// The filter coefficients are derived for the clip's sample rate rather than using the 48kHz constants.
+func kWeight(c *Clip) []float64 {
+	shelf, highPass := kWeightingFilters(float64(c.SampleRate))
+
+	out := make([]float64, len(c.Samples))
+	for ch := 0; ch < c.Channels; ch++ {
+		var s1, s2 biquadState
+		for i := ch; i < len(c.Samples); i += c.Channels {
+			out[i] = s2.process(highPass, s1.process(shelf, float64(c.Samples[i])))
+		}
+	}
+	return out
+}
+
+// biquad holds normalised biquad filter coefficients.
+type biquad struct {
+	b0, b1, b2, a1, a2 float64
+}
+
+// biquadState is the delay line of a direct form I biquad.
+type biquadState struct {
+	x1, x2, y1, y2 float64
+}
+
+func (s *biquadState) process(f biquad, x float64) float64 {
+	y := f.b0*x + f.b1*s.x1 + f.b2*s.x2 - f.a1*s.y1 - f.a2*s.y2
+	s.x2, s.x1 = s.x1, x
+	s.y2, s.y1 = s.y1, y
+	return y
+}
+
+// kWeightingFilters returns the high shelf and high pass stages of the K-weighting filter for a sample rate.
+// The analog prototypes are those used by libebur128.
+func kWeightingFilters(sampleRate float64) (biquad, biquad) {
+	// Stage 1: high shelf modelling the acoustic effect of the head.
+	f0 := 1681.974450955533
+	gain := 3.999843853973347
+	q := 0.7071752369554196
+	k := math.Tan(math.Pi * f0 / sampleRate)
+	vh := math.Pow(10, gain/20)
+	vb := math.Pow(vh, 0.4996667741545416)
+	a0 := 1 + k/q + k*k
+	shelf := biquad{
+		b0: (vh + vb*k/q + k*k) / a0,
+		b1: 2 * (k*k - vh) / a0,
+		b2: (vh - vb*k/q + k*k) / a0,
+		a1: 2 * (k*k - 1) / a0,
+		a2: (1 - k/q + k*k) / a0,
+	}
+
+	// Stage 2: high pass removing low frequencies.
+	f0 = 38.13547087602444
+	q = 0.5003270373238773
+	k = math.Tan(math.Pi * f0 / sampleRate)
+	a0 = 1 + k/q + k*k
+	highPass := biquad{
+		b0: 1,
+		b1: -2,
+		b2: 1,
+		a1: 2 * (k*k - 1) / a0,
+		a2: (1 - k/q + k*k) / a0,
+	}
+
+	return shelf, highPass
+}
+
+// Normalize returns a copy of the clip with its integrated loudness set to the target,
+// limited so its true peak doesn't exceed the target's ceiling.
+// Silent clips are returned unchanged.
+func Normalize(c *Clip, target LoudnessTarget) (*Clip, error) {
+	if err := target.Validate(); err != nil {
+		return nil, err
+	}
+
+	out := c.clone()
+	loudness := Loudness(c)
+	if math.IsInf(loudness, -1) {
+		return out, nil
+	}
+
+	out.applyGain(dbToGain(target.Integrated - loudness))
+	return Limit(out, target.TruePeak), nil
+}
+
+// applyGain multiplies every sample by gain.
+func (c *Clip) applyGain(gain float64) {
+	for i, s := range c.Samples {
+		c.Samples[i] = float32(float64(s) * gain)
+	}
+}
+
+// dbToGain converts decibels to a linear gain.
+func dbToGain(db float64) float64 {
+	return math.Pow(10, db/20)
+}
+
+// gainToDB converts a linear gain to decibels.
+func gainToDB(gain float64) float64 {
+	return 20 * math.Log10(gain)
+}
@@ -0,0 +1,113 @@
+package audio
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sine returns a mono PCM16 clip of a sine wave.
+func sine(sampleRate int, frequency, amplitude, phase float64, d time.Duration) *Clip {
+	frames := int(d.Seconds() * float64(sampleRate))
+	samples := make([]float32, frames)
+	for i := range samples {
+		samples[i] = float32(amplitude * math.Sin(2*math.Pi*frequency*float64(i)/float64(sampleRate)+phase))
+	}
+	return &Clip{SampleRate: sampleRate, Channels: 1, Format: PCM16, Samples: samples}
+}
+
+func TestLoudness(t *testing.T) {
+	// BS.1770 specifies a full scale 1kHz sine in one channel measures -3.01 LUFS.
+	for _, rate := range []int{22050, 24000, 44100, 48000} {
+		clip := sine(rate, 1000, 1, 0, 2*time.Second)
+		assert.InDelta(t, -3.01, Loudness(clip), 0.05, "Sample rate %d", rate)
+	}
+
+	clip := sine(48000, 1000, dbToGain(-20), 0, 2*time.Second)
+	assert.InDelta(t, -23.01, Loudness(clip), 0.05)
+
+	assert.True(t, math.IsInf(Loudness(Silence(48000, 1, PCM16, time.Second)), -1), "Silence has no loudness")
+}
+
+func TestLoudness_Gating(t *testing.T) {
+	// Silence is gated out, so padding speech with it barely changes the measurement.
+	// Without gating, 4s of silence after 2s of tone would measure 4.8dB quieter.
+	tone := sine(48000, 1000, 0.1, 0, 2*time.Second)
+	padded, err := Concat(tone, Silence(48000, 1, PCM16, 4*time.Second))
+	require.NoError(t, err)
+
+	assert.InDelta(t, Loudness(tone), Loudness(padded), 0.5)
+}
+
+func TestTruePeak(t *testing.T) {
+	// A sine at a quarter of the sample rate, offset by 45°, never has a sample on its peak.
+	// Its samples peak at -3dB but the true peak is 0dBTP.
+	clip := sine(48000, 12000, 1, math.Pi/4, 100*time.Millisecond)
+	assert.InDelta(t, -3.01, gainToDB(float64(maxAbs(clip.Samples))), 0.01)
+	assert.InDelta(t, 0, TruePeak(clip), 0.2)
+}
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name   string
+		clip   *Clip
+		target LoudnessTarget
+	}{
+		{name: "Quiet to broadcast", clip: sine(24000, 440, 0.01, 0, 2*time.Second), target: LoudnessTarget{Integrated: -23, TruePeak: -1}},
+		{name: "Loud to broadcast", clip: sine(22050, 440, 0.9, 0, 2*time.Second), target: LoudnessTarget{Integrated: -23, TruePeak: -1}},
+		{name: "Web speech", clip: sine(24000, 300, 0.2, 0, 2*time.Second), target: LoudnessTarget{Integrated: -16, TruePeak: -1.5}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := tt.clip.clone()
+			out, err := Normalize(tt.clip, tt.target)
+			require.NoError(t, err)
+
+			assert.InDelta(t, tt.target.Integrated, Loudness(out), 0.1)
+			assert.LessOrEqual(t, TruePeak(out), tt.target.TruePeak+0.01)
+			assert.Equal(t, original, tt.clip, "The input clip should not be modified")
+		})
+	}
+}
+
+func TestNormalize_Limits(t *testing.T) {
+	// Reaching -8 LUFS with a sine would need peaks well above -1dBTP.
+	out, err := Normalize(sine(48000, 440, 0.1, 0, time.Second), LoudnessTarget{Integrated: -8, TruePeak: -1})
+	require.NoError(t, err)
+	assert.LessOrEqual(t, TruePeak(out), -1+0.01)
+}
+
+func TestNormalize_Silence(t *testing.T) {
+	silence := Silence(24000, 1, PCM16, time.Second)
+	out, err := Normalize(silence, LoudnessTarget{Integrated: -16, TruePeak: -1})
+	require.NoError(t, err)
+	assert.Equal(t, silence, out)
+}
+
+func TestLoudnessTarget_Validate(t *testing.T) {
+	assert.NoError(t, LoudnessTarget{Integrated: -23, TruePeak: -1}.Validate())
+	assert.Error(t, LoudnessTarget{}.Validate())
+	assert.Error(t, LoudnessTarget{Integrated: -80, TruePeak: -1}.Validate())
+	assert.Error(t, LoudnessTarget{Integrated: -16, TruePeak: 1}.Validate())
+}
+
+func TestLimit(t *testing.T) {
+	clip := sine(24000, 200, 1, 0, 500*time.Millisecond)
+	out := Limit(clip, -6)
+	assert.LessOrEqual(t, TruePeak(out), -6+0.01)
+
+	quiet := sine(24000, 200, 0.1, 0, 500*time.Millisecond)
+	assert.Equal(t, quiet, Limit(quiet, -1), "Clips below the ceiling should be unchanged")
+}
+
+func maxAbs(samples []float32) float32 {
+	var peak float32
+	for _, s := range samples {
+		peak = max(peak, s, -s)
+	}
+	return peak
+}
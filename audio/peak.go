@@ -0,0 +1,125 @@
+package audio
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	// oversampling is the factor true peaks are measured at, as recommended by BS.1770-4 for rates up to 96kHz.
+	oversampling = 4
+	// interpolationTaps is the number of input samples on each side used to interpolate between samples.
+	interpolationTaps = 8
+	// limiterAttack is how far ahead of a peak the limiter starts reducing the gain.
+	limiterAttack = 5 * time.Millisecond
+	// limiterRelease is how long the limiter takes to return to full gain after a peak.
+	limiterRelease = 50 * time.Millisecond
+)
+
+// TruePeak returns the true peak level of the clip in dBTP,
+// measured by interpolating between samples at 4x oversampling.
+// Returns -Inf for silence.
+func TruePeak(c *Clip) float64 {
+	var peak float64
+	for _, p := range framePeaks(c) {
+		peak = math.Max(peak, p)
+	}
+	return gainToDB(peak)
+}
+
+// Limit returns a copy of the clip with its gain reduced wherever the true peak would exceed ceiling dBTP.
+// The gain is lowered smoothly ahead of each peak and recovers after it, so peaks are tamed without clicks.
+func Limit(c *Clip, ceiling float64) *Clip {
+	out := c.clone()
+	limit := dbToGain(ceiling)
+	peaks := framePeaks(c)
+
+	gains := make([]float64, len(peaks))
+	var limited bool
+	for i, p := range peaks {
+		gains[i] = 1
+		if p > limit {
+			gains[i] = limit / p
+			limited = true
+		}
+	}
+	if !limited {
+		return out
+	}
+
+	// Ramp the gain down ahead of each peak, then let it recover, never exceeding the gain a peak requires.
+	attack := 1 / math.Max(1, limiterAttack.Seconds()*float64(c.SampleRate))
+	release := 1 / math.Max(1, limiterRelease.Seconds()*float64(c.SampleRate))
+	for i := len(gains) - 2; i >= 0; i-- {
+		gains[i] = math.Min(gains[i], gains[i+1]+attack)
+	}
+	for i := 1; i < len(gains); i++ {
+		gains[i] = math.Min(gains[i], gains[i-1]+release)
+	}
+
+	for frame, gain := range gains {
+		for ch := 0; ch < c.Channels; ch++ {
+			i := frame*c.Channels + ch
+			out.Samples[i] = float32(float64(out.Samples[i]) * gain)
+		}
+	}
+
+	// Changing the gain reshapes the waveform slightly, so catch any overshoot that introduced.
+	if peak := TruePeak(out); peak > ceiling {
+		out.applyGain(dbToGain(ceiling - peak))
+	}
+	return out
+}
+
+// framePeaks returns the absolute true peak around every frame, across all channels.
+// Peaks interpolated between two frames are attributed to both.
+func framePeaks(c *Clip) []float64 {
+	frames := c.Frames()
+	peaks := make([]float64, frames)
+
+	// kernel[phase][j] weighs frame n-interpolationTaps+1+j when interpolating at n+phase/oversampling.
+	var kernel [oversampling][2 * interpolationTaps]float64
+	for phase := 1; phase < oversampling; phase++ {
+		for j := range kernel[phase] {
+			kernel[phase][j] = windowedSinc(float64(interpolationTaps-1-j)+float64(phase)/oversampling, interpolationTaps)
+		}
+	}
+
+	for ch := 0; ch < c.Channels; ch++ {
+		at := func(frame int) float64 {
+			if frame < 0 || frame >= frames {
+				return 0
+			}
+			return float64(c.Samples[frame*c.Channels+ch])
+		}
+
+		for n := 0; n < frames; n++ {
+			peaks[n] = math.Max(peaks[n], math.Abs(at(n)))
+			if n+1 == frames {
+				continue
+			}
+			for phase := 1; phase < oversampling; phase++ {
+				var v float64
+				for j, w := range kernel[phase] {
+					v += at(n-interpolationTaps+1+j) * w
+				}
+				v = math.Abs(v)
+				peaks[n] = math.Max(peaks[n], v)
+				peaks[n+1] = math.Max(peaks[n+1], v)
+			}
+		}
+	}
+	return peaks
+}
+
+// windowedSinc is the sinc function tapered to zero at ±halfWidth by a Hann window.
+func windowedSinc(x, halfWidth float64) float64 {
+	if x == 0 {
+		return 1
+	}
+	if math.Abs(x) >= halfWidth {
+		return 0
+	}
+	px := math.Pi * x
+	return math.Sin(px) / px * 0.5 * (1 + math.Cos(math.Pi*x/halfWidth))
+}
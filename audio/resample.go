@@ -0,0 +1,145 @@
+package audio
+
+import (
+	"fmt"
+	"math"
+)
+
+const (
+	// resampleZeroCrossings is how many zero crossings of the sinc filter are used on each side.
+	// More zero crossings give a sharper cutoff at the cost of speed.
+	resampleZeroCrossings = 32
+	// resampleRolloff places the filter cutoff just below the Nyquist frequency,
+	// leaving room for the transition band so nothing above Nyquist aliases back.
+	resampleRolloff = 0.95
+	// resampleKaiserBeta shapes the Kaiser window, trading transition width for stopband attenuation (about 90dB).
+	resampleKaiserBeta = 8.6
+)
+
+// Resample returns a copy of the clip converted to sampleRate.
+// It uses a Kaiser-windowed sinc filter applied in polyphase form, which low-pass filters
+// the audio when downsampling so frequencies above the new Nyquist frequency don't alias.
+func Resample(c *Clip, sampleRate int) (*Clip, error) {
+	if sampleRate <= 0 {
+		return nil, fmt.Errorf("invalid sample rate: %d", sampleRate)
+	}
+	if sampleRate == c.SampleRate {
+		return c.clone(), nil
+	}
+
+	// The output is at up/down times the input rate, reduced so the filter has as few phases as possible.
+	g := gcd(c.SampleRate, sampleRate)
+	up, down := sampleRate/g, c.SampleRate/g
+
+	cutoff := resampleRolloff * math.Min(1, float64(sampleRate)/float64(c.SampleRate))
+	radius := int(math.Ceil(resampleZeroCrossings / cutoff))
+
+	// phases[p][j] weighs input frame n-radius+1+j for an output frame p/up of the way past input frame n.
+	phases := make([][]float64, up)
+	for p := range phases {
+		taps := make([]float64, 2*radius)
+		offset := float64(p) / float64(up)
+		for j := range taps {
+			d := float64(radius-1-j) + offset
+			taps[j] = cutoff * kaiserSinc(cutoff*d, resampleZeroCrossings)
+		}
+		phases[p] = taps
+	}
+
+	frames := c.Frames()
+	outFrames := int((int64(frames)*int64(up) + int64(down) - 1) / int64(down))
+	out := &Clip{
+		SampleRate: sampleRate,
+		Channels:   c.Channels,
+		Format:     c.Format,
+		Samples:    make([]float32, outFrames*c.Channels),
+	}
+
+	for m := 0; m < outFrames; m++ {
+		pos := int64(m) * int64(down)
+		n := int(pos / int64(up))
+		taps := phases[pos%int64(up)]
+		first := n - radius + 1
+
+		for ch := 0; ch < c.Channels; ch++ {
+			var v float64
+			for j, w := range taps {
+				k := first + j
+				if k < 0 || k >= frames {
+					continue
+				}
+				v += float64(c.Samples[k*c.Channels+ch]) * w
+			}
+			out.Samples[m*c.Channels+ch] = float32(v)
+		}
+	}
+	return out, nil
+}
+
+// Remix returns a copy of the clip with the given number of channels.
+// Converting to mono averages the channels; converting mono to more channels copies it to each of them.
+func Remix(c *Clip, channels int) (*Clip, error) {
+	if channels <= 0 {
+		return nil, fmt.Errorf("invalid channel count: %d", channels)
+	}
+	if channels == c.Channels {
+		return c.clone(), nil
+	}
+	if channels != 1 && c.Channels != 1 {
+		return nil, fmt.Errorf("cannot convert %d channels to %d, only to or from mono", c.Channels, channels)
+	}
+
+	frames := c.Frames()
+	out := &Clip{
+		SampleRate: c.SampleRate,
+		Channels:   channels,
+		Format:     c.Format,
+		Samples:    make([]float32, frames*channels),
+	}
+	for f := 0; f < frames; f++ {
+		if channels == 1 {
+			var sum float64
+			for _, s := range c.Samples[f*c.Channels : (f+1)*c.Channels] {
+				sum += float64(s)
+			}
+			out.Samples[f] = float32(sum / float64(c.Channels))
+			continue
+		}
+		for ch := 0; ch < channels; ch++ {
+			out.Samples[f*channels+ch] = c.Samples[f]
+		}
+	}
+	return out, nil
+}
+
+// kaiserSinc is the sinc function tapered to zero at ±halfWidth by a Kaiser window.
+func kaiserSinc(x, halfWidth float64) float64 {
+	if math.Abs(x) >= halfWidth {
+		return 0
+	}
+	r := x / halfWidth
+	window := besselI0(resampleKaiserBeta*math.Sqrt(1-r*r)) / besselI0(resampleKaiserBeta)
+	if x == 0 {
+		return window
+	}
+	px := math.Pi * x
+	return math.Sin(px) / px * window
+}
+
+// besselI0 is the zeroth order modified Bessel function of the first kind, used by the Kaiser window.
+func besselI0(x float64) float64 {
+	sum, term := 1.0, 1.0
+	for k := 1; term > sum*1e-12; k++ {
+		f := x / (2 * float64(k))
+		term *= f * f
+		sum += term
+	}
+	return sum
+}
+
+func gcd(a, b int) int {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
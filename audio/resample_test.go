@@ -0,0 +1,97 @@
+package audio
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResample(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to int
+	}{
+		{name: "XTTS to wideband telephony", from: 24000, to: 16000},
+		{name: "XTTS to narrowband telephony", from: 24000, to: 8000},
+		{name: "VITS to video", from: 22050, to: 48000},
+		{name: "VITS to wideband telephony", from: 22050, to: 16000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clip := sine(tt.from, 1000, 0.5, 0, time.Second)
+			out, err := Resample(clip, tt.to)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.to, out.SampleRate)
+			assert.InDelta(t, time.Second, out.Duration(), float64(time.Millisecond))
+
+			// Away from the edges, the output should match the same tone generated at the new rate.
+			expected := sine(tt.to, 1000, 0.5, 0, time.Second)
+			edge := tt.to / 20
+			for i := edge; i < len(expected.Samples)-edge; i++ {
+				require.InDelta(t, expected.Samples[i], out.Samples[i], 1e-3, "Sample %d", i)
+			}
+		})
+	}
+}
+
+func TestResample_AntiAliasing(t *testing.T) {
+	// 6kHz is above the 4kHz Nyquist frequency of 8kHz audio, so it has to be filtered out rather than folded down to 2kHz.
+	out, err := Resample(sine(24000, 6000, 0.5, 0, time.Second), 8000)
+	require.NoError(t, err)
+
+	edge := 400
+	quiet := &Clip{SampleRate: 8000, Channels: 1, Format: PCM16, Samples: out.Samples[edge : len(out.Samples)-edge]}
+	assert.Less(t, gainToDB(quiet.rms(0, quiet.Frames())), -70.0)
+}
+
+func TestResample_Stereo(t *testing.T) {
+	left := sine(24000, 500, 0.5, 0, 100*time.Millisecond)
+	stereo := &Clip{SampleRate: 24000, Channels: 2, Format: PCM16}
+	for _, s := range left.Samples {
+		stereo.Samples = append(stereo.Samples, s, 0)
+	}
+
+	out, err := Resample(stereo, 48000)
+	require.NoError(t, err)
+	assert.Equal(t, 2, out.Channels)
+	for i := 1; i < len(out.Samples); i += 2 {
+		require.Zero(t, out.Samples[i], "Channels should be resampled separately")
+	}
+}
+
+func TestResample_SameRate(t *testing.T) {
+	clip := sine(16000, 440, 0.5, 0, 10*time.Millisecond)
+	out, err := Resample(clip, 16000)
+	require.NoError(t, err)
+	assert.Equal(t, clip, out)
+
+	_, err = Resample(clip, 0)
+	assert.Error(t, err)
+}
+
+func TestRemix(t *testing.T) {
+	stereo := &Clip{SampleRate: 8000, Channels: 2, Format: PCM16, Samples: []float32{0.5, 0.1, -0.2, -0.4}}
+
+	mono, err := Remix(stereo, 1)
+	require.NoError(t, err)
+	assert.InDeltaSlice(t, []float32{0.3, -0.3}, mono.Samples, 1e-6)
+
+	back, err := Remix(mono, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []float32{mono.Samples[0], mono.Samples[0], mono.Samples[1], mono.Samples[1]}, back.Samples)
+
+	surround := &Clip{SampleRate: 8000, Channels: 6, Format: PCM16, Samples: make([]float32, 6)}
+	_, err = Remix(surround, 2)
+	assert.Error(t, err, "Only conversions to or from mono are supported")
+}
+
+func TestBesselI0(t *testing.T) {
+	assert.InDelta(t, 1, besselI0(0), 1e-12)
+	assert.InDelta(t, 27.239871823604442, besselI0(5), 1e-9)
+	assert.False(t, math.IsNaN(besselI0(resampleKaiserBeta)))
+}
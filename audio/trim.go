@@ -0,0 +1,100 @@
+package audio
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// trimWindow is the length of the windows whose energy is compared against the trim threshold.
+const trimWindow = 10 * time.Millisecond
+
+// TrimOptions controls how leading and trailing silence is trimmed.
+type TrimOptions struct {
+	// Threshold is the level in dBFS below which a window counts as silence, e.g. -45.
+	// It's compared against the RMS energy of 10ms windows, so quiet breaths can be trimmed too.
+	Threshold float64
+	// MinSilence is the shortest leading or trailing silence that is trimmed.
+	// Shorter silences are left as they are.
+	MinSilence time.Duration
+	// PadStart is the silence put back before the audio once leading silence is trimmed.
+	PadStart time.Duration
+	// PadEnd is the silence put back after the audio once trailing silence is trimmed.
+	PadEnd time.Duration
+}
+
+// Validate checks that the options are usable.
+func (o TrimOptions) Validate() error {
+	if o.Threshold >= 0 {
+		return fmt.Errorf("threshold must be below 0 dBFS, got %v", o.Threshold)
+	}
+	if o.MinSilence < 0 || o.PadStart < 0 || o.PadEnd < 0 {
+		return fmt.Errorf("trim durations cannot be negative")
+	}
+	return nil
+}
+
+// Trim returns a copy of the clip with leading and trailing silence replaced by fixed padding.
+// A clip that is silent throughout is reduced to just the padding.
+func Trim(c *Clip, opts TrimOptions) (*Clip, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	window := max(1, int(trimWindow.Seconds()*float64(c.SampleRate)))
+	threshold := dbToGain(opts.Threshold)
+	frames := c.Frames()
+
+	// Find the first and last windows with sound, then the frames they cover.
+	start, end := frames, frames
+	for w := 0; w*window < frames; w++ {
+		if c.rms(w*window, min(frames, (w+1)*window)) >= threshold {
+			start = w * window
+			break
+		}
+	}
+	for w := (frames - 1) / window; w >= 0 && start < frames; w-- {
+		if c.rms(w*window, min(frames, (w+1)*window)) >= threshold {
+			end = min(frames, (w+1)*window)
+			break
+		}
+	}
+
+	minSilence := int(opts.MinSilence.Seconds() * float64(c.SampleRate))
+	var before, after time.Duration
+	if start < frames {
+		if start >= minSilence {
+			before = opts.PadStart
+		} else {
+			start = 0
+		}
+		if frames-end >= minSilence {
+			after = opts.PadEnd
+		} else {
+			end = frames
+		}
+	} else {
+		// Nothing but silence.
+		start, end = 0, 0
+		before, after = opts.PadStart, opts.PadEnd
+	}
+
+	out := Silence(c.SampleRate, c.Channels, c.Format, before)
+	out.Samples = append(out.Samples, c.Samples[start*c.Channels:end*c.Channels]...)
+	out.Samples = append(out.Samples, Silence(c.SampleRate, c.Channels, c.Format, after).Samples...)
+	return out, nil
+}
+
+// rms returns the root mean square of the frames in [start, end), across all channels.
+func (c *Clip) rms(start, end int) float64 {
+	samples := c.Samples[start*c.Channels : end*c.Channels]
+	if len(samples) == 0 {
+		return 0
+	}
+
+	var sum float64
+	for _, s := range samples {
+		sum += float64(s) * float64(s)
+	}
+	return math.Sqrt(sum / float64(len(samples)))
+}
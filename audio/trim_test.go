@@ -0,0 +1,61 @@
+package audio
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrim(t *testing.T) {
+	// 300ms of faint noise, 200ms of tone, then 500ms of silence.
+	noise := sine(8000, 50, 0.001, 0, 300*time.Millisecond)
+	tone := sine(8000, 440, 0.5, 0, 200*time.Millisecond)
+	clip, err := Concat(noise, tone, Silence(8000, 1, PCM16, 500*time.Millisecond))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		opts     TrimOptions
+		expected time.Duration
+	}{
+		{name: "Trim both ends", opts: TrimOptions{Threshold: -45}, expected: 200 * time.Millisecond},
+		{name: "Fixed padding", opts: TrimOptions{Threshold: -45, PadStart: 50 * time.Millisecond, PadEnd: 100 * time.Millisecond}, expected: 350 * time.Millisecond},
+		{name: "Short silence is kept", opts: TrimOptions{Threshold: -45, MinSilence: 400 * time.Millisecond}, expected: 500 * time.Millisecond},
+		{name: "Noise above threshold is kept", opts: TrimOptions{Threshold: -70}, expected: 500 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := Trim(clip, tt.opts)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, out.Duration())
+			assert.Equal(t, time.Second, clip.Duration(), "The input clip should not be modified")
+		})
+	}
+}
+
+func TestTrim_Padding(t *testing.T) {
+	tone := sine(8000, 440, 0.5, 0, 100*time.Millisecond)
+	clip, err := Concat(Silence(8000, 1, PCM16, time.Second), tone)
+	require.NoError(t, err)
+
+	out, err := Trim(clip, TrimOptions{Threshold: -40, PadStart: 10 * time.Millisecond})
+	require.NoError(t, err)
+	assert.Equal(t, make([]float32, 80), out.Samples[:80], "Padding should be silence")
+	assert.Equal(t, tone.Samples, out.Samples[80:])
+}
+
+func TestTrim_Silence(t *testing.T) {
+	out, err := Trim(Silence(8000, 2, PCM16, time.Second), TrimOptions{Threshold: -40, PadStart: 20 * time.Millisecond, PadEnd: 30 * time.Millisecond})
+	require.NoError(t, err)
+	assert.Equal(t, 50*time.Millisecond, out.Duration())
+	assert.Equal(t, 2, out.Channels)
+}
+
+func TestTrimOptions_Validate(t *testing.T) {
+	assert.NoError(t, TrimOptions{Threshold: -40}.Validate())
+	assert.Error(t, TrimOptions{}.Validate())
+	assert.Error(t, TrimOptions{Threshold: -40, PadEnd: -time.Second}.Validate())
+}
@@ -0,0 +1,254 @@
+package audio
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+)
+
+// Decode reads a WAV file from r.
+// A data chunk that is shorter than its declared size, as written by streaming encoders, is read up to its last whole frame.
+func Decode(r io.Reader) (*Clip, error) {
+	var riff [12]byte
+	if _, err := io.ReadFull(r, riff[:]); err != nil {
+		return nil, fmt.Errorf("failed to read RIFF header: %w", err)
+	}
+	if string(riff[0:4]) != "RIFF" || string(riff[8:12]) != "WAVE" {
+		return nil, errors.New("not a WAV file")
+	}
+
+	var clip *Clip
+	for {
+		var chunk [8]byte
+		if _, err := io.ReadFull(r, chunk[:]); err != nil {
+			return nil, fmt.Errorf("failed to read WAV chunk: %w", err)
+		}
+		id := string(chunk[0:4])
+		size := int64(binary.LittleEndian.Uint32(chunk[4:8]))
+
+		switch id {
+		case "fmt ":
+			c, err := readFmt(r, size)
+			if err != nil {
+				return nil, err
+			}
+			clip = c
+		case "data":
+			if clip == nil {
+				return nil, errors.New("data chunk found before fmt chunk")
+			}
+			data, err := io.ReadAll(io.LimitReader(r, size))
+			if err != nil {
+				return nil, fmt.Errorf("failed to read data chunk: %w", err)
+			}
+			clip.Samples = decodeSamples(data, clip.Format, clip.Channels)
+			return clip, nil
+		default:
+			if _, err := io.CopyN(io.Discard, r, size); err != nil {
+				return nil, fmt.Errorf("failed to skip %q chunk: %w", id, err)
+			}
+		}
+
+		// Chunks are padded to an even size.
+		if size%2 == 1 {
+			if _, err := io.CopyN(io.Discard, r, 1); err != nil {
+				return nil, fmt.Errorf("failed to skip chunk padding: %w", err)
+			}
+		}
+	}
+}
+
+// DecodeBytes reads a WAV file held in memory.
+func DecodeBytes(data []byte) (*Clip, error) {
+	return Decode(bytes.NewReader(data))
+}
+
+// ReadFile reads the WAV file at path.
+func ReadFile(path string) (*Clip, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Decode(bufio.NewReader(f))
+}
+
+// readFmt reads a "fmt " chunk into a clip without samples.
+func readFmt(r io.Reader, size int64) (*Clip, error) {
+	if size < 16 {
+		return nil, errors.New("invalid fmt chunk")
+	}
+	chunk := make([]byte, size)
+	if _, err := io.ReadFull(r, chunk); err != nil {
+		return nil, fmt.Errorf("failed to read fmt chunk: %w", err)
+	}
+
+	tag := binary.LittleEndian.Uint16(chunk[0:2])
+	channels := int(binary.LittleEndian.Uint16(chunk[2:4]))
+	sampleRate := int(binary.LittleEndian.Uint32(chunk[4:8]))
+	bitsPerSample := int(binary.LittleEndian.Uint16(chunk[14:16]))
+
+	// WAVE_FORMAT_EXTENSIBLE stores the real format tag at the start of its sub-format GUID.
+	if tag == tagExtensible {
+		if size < 26 {
+			return nil, errors.New("invalid extensible fmt chunk")
+		}
+		tag = binary.LittleEndian.Uint16(chunk[24:26])
+	}
+
+	format, err := formatFor(tag, bitsPerSample)
+	if err != nil {
+		return nil, err
+	}
+	if channels == 0 || sampleRate == 0 {
+		return nil, fmt.Errorf("invalid fmt chunk: %d channels at %dHz", channels, sampleRate)
+	}
+
+	return &Clip{
+		SampleRate: sampleRate,
+		Channels:   channels,
+		Format:     format,
+	}, nil
+}
+
+// decodeSamples converts raw little-endian sample data to samples scaled to [-1, 1].
+// Trailing bytes that don't make up a whole frame are dropped.
+func decodeSamples(data []byte, format Format, channels int) []float32 {
+	size := format.bytesPerSample()
+	frameSize := size * channels
+	data = data[:len(data)-len(data)%frameSize]
+
+	samples := make([]float32, len(data)/size)
+	for i := range samples {
+		b := data[i*size : (i+1)*size]
+		switch format {
+		case PCM16:
+			samples[i] = float32(int16(binary.LittleEndian.Uint16(b))) / (1 << 15)
+		case PCM24:
+			v := int32(b[0]) | int32(b[1])<<8 | int32(int8(b[2]))<<16
+			samples[i] = float32(v) / (1 << 23)
+		case Float32:
+			samples[i] = math.Float32frombits(binary.LittleEndian.Uint32(b))
+		case MuLaw:
+			samples[i] = float32(muLawDecode(b[0])) / (1 << 15)
+		case ALaw:
+			samples[i] = float32(aLawDecode(b[0])) / (1 << 15)
+		}
+	}
+	return samples
+}
+
+// Encode writes the clip to w as a WAV file in the clip's format.
+// G.711 clips are written with the cbSize field and fact chunk that non-PCM WAV files require.
+func (c *Clip) Encode(w io.Writer) error {
+	if err := c.Validate(); err != nil {
+		return err
+	}
+
+	data := encodeSamples(c.Samples, c.Format)
+	bits := c.Format.BitsPerSample()
+	blockAlign := c.Channels * bits / 8
+	g711 := c.Format == MuLaw || c.Format == ALaw
+
+	headerSize := 36
+	if g711 {
+		headerSize += 2 + 12
+	}
+
+	var buf bytes.Buffer
+	buf.Grow(headerSize + 8 + len(data) + 1)
+	buf.WriteString("RIFF")
+	binary.Write(&buf, binary.LittleEndian, uint32(headerSize+len(data)+len(data)%2))
+	buf.WriteString("WAVE")
+
+	buf.WriteString("fmt ")
+	if g711 {
+		binary.Write(&buf, binary.LittleEndian, uint32(18))
+	} else {
+		binary.Write(&buf, binary.LittleEndian, uint32(16))
+	}
+	binary.Write(&buf, binary.LittleEndian, c.Format.tag())
+	binary.Write(&buf, binary.LittleEndian, uint16(c.Channels))
+	binary.Write(&buf, binary.LittleEndian, uint32(c.SampleRate))
+	binary.Write(&buf, binary.LittleEndian, uint32(c.SampleRate*blockAlign))
+	binary.Write(&buf, binary.LittleEndian, uint16(blockAlign))
+	binary.Write(&buf, binary.LittleEndian, uint16(bits))
+	if g711 {
+		binary.Write(&buf, binary.LittleEndian, uint16(0)) // cbSize, no extra format bytes.
+		buf.WriteString("fact")
+		binary.Write(&buf, binary.LittleEndian, uint32(4))
+		binary.Write(&buf, binary.LittleEndian, uint32(c.Frames()))
+	}
+
+	buf.WriteString("data")
+	binary.Write(&buf, binary.LittleEndian, uint32(len(data)))
+	buf.Write(data)
+	if len(data)%2 == 1 {
+		buf.WriteByte(0)
+	}
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// Bytes returns the clip encoded as a WAV file.
+func (c *Clip) Bytes() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := c.Encode(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// EncodePCM writes the clip's samples to w as raw little-endian PCM in the clip's format, without a header.
+// G.711 clips are written as raw G.711, one companded byte per sample.
+func (c *Clip) EncodePCM(w io.Writer) error {
+	if err := c.Validate(); err != nil {
+		return err
+	}
+	_, err := w.Write(encodeSamples(c.Samples, c.Format))
+	return err
+}
+
+// WriteFile writes the clip to path as a WAV file.
+func (c *Clip) WriteFile(path string) error {
+	data, err := c.Bytes()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// encodeSamples converts samples to raw little-endian sample data, clipping anything outside [-1, 1].
+func encodeSamples(samples []float32, format Format) []byte {
+	size := format.bytesPerSample()
+	data := make([]byte, len(samples)*size)
+	for i, s := range samples {
+		b := data[i*size : (i+1)*size]
+		switch format {
+		case PCM16:
+			binary.LittleEndian.PutUint16(b, uint16(int16(quantize(s, 1<<15))))
+		case PCM24:
+			v := quantize(s, 1<<23)
+			b[0], b[1], b[2] = byte(v), byte(v>>8), byte(v>>16)
+		case Float32:
+			binary.LittleEndian.PutUint32(b, math.Float32bits(s))
+		case MuLaw:
+			b[0] = muLawEncode(int16(quantize(s, 1<<15)))
+		case ALaw:
+			b[0] = aLawEncode(int16(quantize(s, 1<<15)))
+		}
+	}
+	return data
+}
+
+// quantize scales s to a signed integer with the given full scale, clipping it to the representable range.
+func quantize(s float32, scale float64) int32 {
+	v := math.Round(float64(s) * scale)
+	return int32(math.Max(-scale, math.Min(scale-1, v)))
+}
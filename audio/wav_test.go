@@ -0,0 +1,152 @@
+package audio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// wavFile builds a WAV file with the given fmt chunk fields and raw sample data,
+// with an unrelated "LIST" chunk in front of the data to check it's skipped.
+func wavFile(tag uint16, channels, sampleRate, bits int, data []byte) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("RIFF")
+	binary.Write(&buf, binary.LittleEndian, uint32(4+24+10+8+len(data)))
+	buf.WriteString("WAVE")
+	buf.WriteString("fmt ")
+	binary.Write(&buf, binary.LittleEndian, uint32(16))
+	binary.Write(&buf, binary.LittleEndian, tag)
+	binary.Write(&buf, binary.LittleEndian, uint16(channels))
+	binary.Write(&buf, binary.LittleEndian, uint32(sampleRate))
+	binary.Write(&buf, binary.LittleEndian, uint32(sampleRate*channels*bits/8))
+	binary.Write(&buf, binary.LittleEndian, uint16(channels*bits/8))
+	binary.Write(&buf, binary.LittleEndian, uint16(bits))
+	buf.WriteString("LIST")
+	binary.Write(&buf, binary.LittleEndian, uint32(1))
+	buf.WriteString("x\x00")
+	buf.WriteString("data")
+	binary.Write(&buf, binary.LittleEndian, uint32(len(data)))
+	buf.Write(data)
+	return buf.Bytes()
+}
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     []byte
+		format   Format
+		expected []float32
+	}{
+		{
+			name:     "PCM16",
+			file:     wavFile(tagPCM, 2, 8000, 16, []byte{0x00, 0x40, 0x00, 0xC0}),
+			format:   PCM16,
+			expected: []float32{0.5, -0.5},
+		},
+		{
+			name:     "PCM24",
+			file:     wavFile(tagPCM, 1, 8000, 24, []byte{0x00, 0x00, 0x40, 0x00, 0x00, 0x80}),
+			format:   PCM24,
+			expected: []float32{0.5, -1},
+		},
+		{
+			name:     "Float32",
+			file:     wavFile(tagFloat, 1, 8000, 32, binary.LittleEndian.AppendUint32(nil, 0x3F000000)),
+			format:   Float32,
+			expected: []float32{0.5},
+		},
+		{
+			name:     "MuLaw",
+			file:     wavFile(tagMuLaw, 1, 8000, 8, []byte{0xFF, 0x80}),
+			format:   MuLaw,
+			expected: []float32{0, 32124.0 / 32768},
+		},
+		{
+			name:     "ALaw",
+			file:     wavFile(tagALaw, 1, 8000, 8, []byte{0xD5, 0x2A}),
+			format:   ALaw,
+			expected: []float32{8.0 / 32768, -32256.0 / 32768},
+		},
+		{
+			name:     "Partial frame is dropped",
+			file:     wavFile(tagPCM, 2, 8000, 16, []byte{0x00, 0x40, 0x00}),
+			format:   PCM16,
+			expected: []float32{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clip, err := DecodeBytes(tt.file)
+			require.NoError(t, err)
+			assert.Equal(t, 8000, clip.SampleRate)
+			assert.Equal(t, tt.format, clip.Format)
+			assert.Equal(t, tt.expected, clip.Samples)
+		})
+	}
+}
+
+func TestDecode_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		file []byte
+	}{
+		{name: "Not a WAV", file: []byte("not a wav file")},
+		{name: "Unsupported format", file: wavFile(tagPCM, 1, 8000, 8, []byte{0x80})},
+		{name: "Truncated", file: wavFile(tagPCM, 1, 8000, 16, nil)[:20]},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := DecodeBytes(tt.file)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestEncode_RoundTrip(t *testing.T) {
+	for _, format := range []Format{PCM16, PCM24, Float32} {
+		t.Run(format.String(), func(t *testing.T) {
+			clip := &Clip{
+				SampleRate: 24000,
+				Channels:   1,
+				Format:     format,
+				Samples:    []float32{0, 0.25, -0.25, 0.5, -1},
+			}
+
+			path := filepath.Join(t.TempDir(), "clip.wav")
+			require.NoError(t, clip.WriteFile(path))
+
+			decoded, err := ReadFile(path)
+			require.NoError(t, err)
+			assert.Equal(t, clip, decoded)
+		})
+	}
+}
+
+func TestEncode_Clips(t *testing.T) {
+	clip := &Clip{SampleRate: 8000, Channels: 1, Format: PCM16, Samples: []float32{2, -2}}
+	data, err := clip.Bytes()
+	require.NoError(t, err)
+
+	decoded, err := DecodeBytes(data)
+	require.NoError(t, err)
+	assert.Equal(t, []float32{32767.0 / 32768, -1}, decoded.Samples, "Out of range samples should be clipped")
+}
+
+func TestEncode_Invalid(t *testing.T) {
+	clip := &Clip{SampleRate: 8000, Channels: 2, Format: PCM16, Samples: []float32{0}}
+	_, err := clip.Bytes()
+	assert.Error(t, err, "Samples that don't fill a frame should be rejected")
+}
+
+func TestClip_Duration(t *testing.T) {
+	clip := &Clip{SampleRate: 48000, Channels: 2, Format: PCM16, Samples: make([]float32, 96000)}
+	assert.Equal(t, 48000, clip.Frames())
+	assert.Equal(t, time.Second, clip.Duration())
+}
@@ -0,0 +1,33 @@
+package coqui
+
+import (
+	"context"
+	"fmt"
+)
+
+// backend synthesizes audio without spawning the Coqui TTS command for every call.
+// When no backend is configured, the command is run through the Runner instead.
+type backend interface {
+	// synthesize renders text using the configuration of t and returns the WAV audio.
+	synthesize(ctx context.Context, t TTS, text string) ([]byte, attemptStats, error)
+	// close releases any resources held by the backend.
+	close() error
+}
+
+// requireCommand returns an error matching ErrNotSupported if a backend is configured,
+// for features that only the tts command offers.
+func (t TTS) requireCommand(feature string) error {
+	if t.backend == nil {
+		return nil
+	}
+	return fmt.Errorf("%w: %s needs the tts command, not the worker or tts-server backend", ErrNotSupported, feature)
+}
+
+// Close releases any resources held by the TTS instance, such as a running synthesis worker.
+// It is safe to call Close on an instance that doesn't hold any resources.
+func (t TTS) Close() error {
+	if t.backend == nil {
+		return nil
+	}
+	return t.backend.close()
+}
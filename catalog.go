@@ -0,0 +1,195 @@
+package coqui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/pixellini/go-coqui/model"
+	"github.com/pixellini/go-coqui/models/tts"
+	"github.com/pixellini/go-coqui/models/vocoder"
+	"github.com/pixellini/go-coqui/models/voiceconversion"
+)
+
+// catalogLine matches a model in the output of "tts --list_models", e.g. " 3: tts_models/en/ek1/tacotron2 [already downloaded]".
+var catalogLine = regexp.MustCompile(`^\s*\d+:\s*(\S+/\S+)(\s+\[already downloaded\])?\s*$`)
+
+// CatalogEntry is a model offered by the installed Coqui TTS.
+type CatalogEntry struct {
+	// Name is the model name as Coqui TTS lists it, e.g. "tts_models/en/ek1/tacotron2".
+	Name string
+	// Identifier describes the model: its preset if the library knows it, and a custom identifier otherwise.
+	// It's the zero value if the name couldn't be parsed, e.g. because the library doesn't know its language.
+	Identifier model.Identifier
+	// Downloaded reports whether Coqui TTS has already downloaded the model.
+	Downloaded bool
+}
+
+// IsPreset reports whether the model is predefined in the tts, vocoder or voiceconversion packages.
+func (e CatalogEntry) IsPreset() bool {
+	return e.Identifier.IsValid() && !e.Identifier.IsCustom
+}
+
+// Catalog lists the models offered by the installed Coqui TTS.
+type Catalog struct {
+	// Entries are the models in the order Coqui TTS lists them.
+	Entries []CatalogEntry
+}
+
+// Lookup returns the entry for a model name.
+func (c *Catalog) Lookup(name string) (CatalogEntry, bool) {
+	for _, e := range c.Entries {
+		if e.Name == name {
+			return e, true
+		}
+	}
+	return CatalogEntry{}, false
+}
+
+// ByType returns the entries of a model type, e.g. model.TypeVocoder.
+func (c *Catalog) ByType(t model.Type) []CatalogEntry {
+	var result []CatalogEntry
+	for _, e := range c.Entries {
+		if e.Identifier.Category == t {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+// Identifiers returns the identifiers of every model that could be parsed.
+func (c *Catalog) Identifiers() []model.Identifier {
+	var result []model.Identifier
+	for _, e := range c.Entries {
+		if e.Identifier.Category != "" {
+			result = append(result, e.Identifier)
+		}
+	}
+	return result
+}
+
+// MissingPresets returns the names of predefined models the installed Coqui TTS doesn't offer.
+// Using them will fail until Coqui TTS is upgraded, or the preset is removed from the library.
+func (c *Catalog) MissingPresets() []string {
+	var missing []string
+	for _, name := range presetNames() {
+		if _, ok := c.Lookup(name); !ok {
+			missing = append(missing, name)
+		}
+	}
+	return missing
+}
+
+// Unknown returns the models offered by the installed Coqui TTS that aren't predefined in the library.
+// They can still be used through the custom identifier in their entry.
+func (c *Catalog) Unknown() []CatalogEntry {
+	var unknown []CatalogEntry
+	for _, e := range c.Entries {
+		if !e.IsPreset() {
+			unknown = append(unknown, e)
+		}
+	}
+	return unknown
+}
+
+// presetNames returns the names of every predefined model, in every language it supports.
+func presetNames() []string {
+	var names []string
+	for _, presets := range [][]model.Identifier{tts.GetPresets(), vocoder.GetPresets(), voiceconversion.GetPresets()} {
+		for _, preset := range presets {
+			for _, name := range preset.NameList() {
+				if !slices.Contains(names, name) {
+					names = append(names, name)
+				}
+			}
+		}
+	}
+	return names
+}
+
+// parseCatalog parses the output of "tts --list_models".
+func parseCatalog(output string) (*Catalog, error) {
+	catalog := &Catalog{}
+	for _, line := range strings.Split(output, "\n") {
+		m := catalogLine.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		id, _ := model.ParseIdentifier(m[1])
+		catalog.Entries = append(catalog.Entries, CatalogEntry{
+			Name:       m[1],
+			Identifier: id,
+			Downloaded: m[2] != "",
+		})
+	}
+
+	if len(catalog.Entries) == 0 {
+		return nil, errors.New("no models found in model list")
+	}
+	return catalog, nil
+}
+
+// catalogCache holds the catalog so it's only listed once.
+// It's shared by pointer between copies of a TTS instance.
+type catalogCache struct {
+	mu      sync.Mutex
+	catalog *Catalog
+}
+
+// Catalog returns the models offered by the installed Coqui TTS.
+// The list is read with "tts --list_models" the first time it's needed, then cached.
+func (t TTS) Catalog(ctx context.Context) (*Catalog, error) {
+	return t.catalogFor(ctx, false)
+}
+
+// RefreshCatalog lists the models offered by the installed Coqui TTS again, replacing the cached catalog.
+// Use it after installing a different version of Coqui TTS.
+func (t TTS) RefreshCatalog(ctx context.Context) (*Catalog, error) {
+	return t.catalogFor(ctx, true)
+}
+
+// catalogFor returns the cached catalog, listing the models if there isn't one or refresh is set.
+// The cache isn't locked while listing, since the command can be slow.
+// A TTS that wasn't created with New has no cache, so the models are listed every time.
+func (t TTS) catalogFor(ctx context.Context, refresh bool) (*Catalog, error) {
+	if t.catalog != nil && !refresh {
+		t.catalog.mu.Lock()
+		cached := t.catalog.catalog
+		t.catalog.mu.Unlock()
+		if cached != nil {
+			return cached, nil
+		}
+	}
+	if err := t.requireCommand("listing models"); err != nil {
+		return nil, err
+	}
+
+	catalog, err := t.listModels(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if t.catalog != nil {
+		t.catalog.mu.Lock()
+		t.catalog.catalog = catalog
+		t.catalog.mu.Unlock()
+	}
+	return catalog, nil
+}
+
+// listModels runs "tts --list_models" and parses its output.
+func (t TTS) listModels(ctx context.Context) (*Catalog, error) {
+	out, err := t.runCommand(ctx, []string{argListModels})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list models: %w", newCommandError(out, err))
+	}
+
+	catalog, err := parseCatalog(string(out.Stdout))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list models: %w", err)
+	}
+	return catalog, nil
+}
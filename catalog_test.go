@@ -0,0 +1,163 @@
+package coqui
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pixellini/go-coqui/model"
+	"github.com/pixellini/go-coqui/models/tts"
+	"github.com/pixellini/go-coqui/models/voiceconversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// listModelsOutput is an excerpt of "tts --list_models" output.
+const listModelsOutput = `
+ Name format: type/language/dataset/model
+ 1: tts_models/multilingual/multi-dataset/xtts_v2 [already downloaded]
+ 2: tts_models/multilingual/multi-dataset/your_tts
+ 3: tts_models/en/ljspeech/tacotron2-DDC
+ 4: tts_models/en/ljspeech/vits [already downloaded]
+ 5: tts_models/en/ljspeech/new-model
+ 6: tts_models/zz/some-dataset/vits
+
+ Name format: type/language/dataset/model
+ 1: vocoder_models/universal/libri-tts/wavegrad
+ 2: vocoder_models/en/ljspeech/hifigan_v2
+
+ Name format: type/language/dataset/model
+ 1: voice_conversion_models/multilingual/vctk/freevc24
+ 2: voice_conversion_models/multilingual/multi-dataset/knnvc
+`
+
+func TestParseCatalog(t *testing.T) {
+	catalog, err := parseCatalog(listModelsOutput)
+	require.NoError(t, err)
+	require.Len(t, catalog.Entries, 10)
+
+	xtts, ok := catalog.Lookup("tts_models/multilingual/multi-dataset/xtts_v2")
+	require.True(t, ok)
+	assert.True(t, xtts.Downloaded)
+	assert.True(t, xtts.IsPreset())
+	assert.Equal(t, tts.PresetXTTSv2, xtts.Identifier)
+
+	custom, ok := catalog.Lookup("tts_models/en/ljspeech/new-model")
+	require.True(t, ok)
+	assert.False(t, custom.Downloaded)
+	assert.False(t, custom.IsPreset())
+	assert.Equal(t, model.BaseModel("new-model"), custom.Identifier.Model)
+
+	assert.Len(t, catalog.ByType(model.TypeVocoder), 2)
+	assert.Equal(t, voiceconversion.PresetMultidataKnnvc, catalog.ByType(model.TypeVoiceConversion)[1].Identifier)
+	assert.Len(t, catalog.Identifiers(), 9, "Models in unknown languages have no identifier")
+}
+
+func TestCatalog_Drift(t *testing.T) {
+	catalog, err := parseCatalog(listModelsOutput)
+	require.NoError(t, err)
+
+	var unknown []string
+	for _, e := range catalog.Unknown() {
+		unknown = append(unknown, e.Name)
+	}
+	assert.Equal(t, []string{"tts_models/en/ljspeech/new-model", "tts_models/zz/some-dataset/vits"}, unknown)
+
+	missing := catalog.MissingPresets()
+	assert.Contains(t, missing, "tts_models/multilingual/multi-dataset/bark")
+	assert.Contains(t, missing, "voice_conversion_models/multilingual/multi-dataset/openvoice_v2")
+	assert.NotContains(t, missing, "tts_models/en/ljspeech/vits")
+	assert.NotContains(t, missing, "vocoder_models/universal/libri-tts/wavegrad")
+}
+
+func TestParseCatalog_Empty(t *testing.T) {
+	_, err := parseCatalog("Name format: type/language/dataset/model\n")
+	assert.Error(t, err)
+}
+
+func TestTTS_Catalog(t *testing.T) {
+	coqui, runner := newTestTTS(t)
+	runner.respond = func(cmd Command) (Output, error) {
+		return Output{Stdout: []byte(listModelsOutput)}, nil
+	}
+
+	catalog, err := coqui.Catalog(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, catalog.Entries, 10)
+
+	cached, err := coqui.Catalog(context.Background())
+	require.NoError(t, err)
+	assert.Same(t, catalog, cached)
+
+	calls := runner.calls()
+	require.Len(t, calls, 1, "The catalog should be cached")
+	assert.Equal(t, []string{argListModels}, calls[0].Args)
+
+	refreshed, err := coqui.RefreshCatalog(context.Background())
+	require.NoError(t, err)
+	assert.NotSame(t, catalog, refreshed)
+	assert.Len(t, runner.calls(), 2)
+}
+
+func TestTTS_Catalog_Failure(t *testing.T) {
+	coqui, runner := newTestTTS(t)
+	runner.respond = func(cmd Command) (Output, error) {
+		return Output{Stderr: []byte("ModuleNotFoundError: No module named 'TTS'")}, errors.New("exit status 1")
+	}
+
+	_, err := coqui.Catalog(context.Background())
+	var cmdErr *CommandError
+	assert.ErrorAs(t, err, &cmdErr)
+
+	runner.respond = func(cmd Command) (Output, error) {
+		return Output{Stdout: []byte(listModelsOutput)}, nil
+	}
+	_, err = coqui.Catalog(context.Background())
+	assert.NoError(t, err, "Failures should not be cached")
+}
+
+func TestTTS_Catalog_DoesNotBlockCache(t *testing.T) {
+	coqui, runner := newTestTTS(t)
+	runner.respond = func(cmd Command) (Output, error) {
+		return Output{Stdout: []byte(listModelsOutput)}, nil
+	}
+	catalog, err := coqui.Catalog(context.Background())
+	require.NoError(t, err)
+
+	started, release := make(chan struct{}), make(chan struct{})
+	runner.respond = func(cmd Command) (Output, error) {
+		close(started)
+		<-release
+		return Output{Stdout: []byte(listModelsOutput)}, nil
+	}
+	refreshed := make(chan error, 1)
+	go func() {
+		_, err := coqui.RefreshCatalog(context.Background())
+		refreshed <- err
+	}()
+	<-started
+
+	cached := make(chan *Catalog, 1)
+	go func() {
+		c, _ := coqui.Catalog(context.Background())
+		cached <- c
+	}()
+	select {
+	case c := <-cached:
+		assert.Same(t, catalog, c)
+	case <-time.After(time.Second):
+		t.Fatal("reading the cached catalog should not wait for a refresh to finish")
+	}
+
+	close(release)
+	require.NoError(t, <-refreshed)
+}
+
+func TestTTS_Catalog_Backend(t *testing.T) {
+	coqui, runner := newTestTTS(t, WithServer("http://localhost:5002"))
+
+	_, err := coqui.Catalog(context.Background())
+	assert.ErrorIs(t, err, ErrNotSupported)
+	assert.Empty(t, runner.calls(), "The CLI should not run when a backend is configured")
+}
@@ -0,0 +1,331 @@
+package coqui
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/pixellini/go-coqui/audio"
+	"github.com/pixellini/go-coqui/model"
+)
+
+// ChunkPolicy controls how long text is split into chunks that are synthesized separately
+// and stitched back together into a single WAV.
+//
+// Text is always split into paragraphs on blank lines, and paragraphs into sentences.
+// Sentences are packed into chunks of up to MaxChars characters; a sentence longer than
+// MaxChars is split at clause breaks (commas, semicolons) or, failing that, between words.
+//
+// Every chunk is a separate synthesis. The tts command loads the model again for each one,
+// which for XTTS takes far longer than the synthesis itself, so text is only split with the
+// tts command or tts-server when a policy is set with WithChunkPolicy. The worker keeps the
+// model loaded between chunks, so it splits text with the default policy.
+type ChunkPolicy struct {
+	// MaxChars is the maximum number of characters in a chunk. 0 means no limit.
+	MaxChars int
+	// SentencePause is the silence inserted between sentences.
+	// When set, every sentence is synthesized as its own chunk so the pause can be inserted.
+	SentencePause time.Duration
+	// ParagraphPause is the silence inserted between paragraphs.
+	ParagraphPause time.Duration
+}
+
+// DefaultChunkPolicy returns the policy used when none is configured.
+// 250 characters keeps chunks within the sentence length XTTS handles well.
+func DefaultChunkPolicy() ChunkPolicy {
+	return ChunkPolicy{
+		MaxChars:       250,
+		ParagraphPause: 500 * time.Millisecond,
+	}
+}
+
+// Validate checks that the policy's values are usable.
+func (p ChunkPolicy) Validate() error {
+	if p.MaxChars < 0 {
+		return fmt.Errorf("max chars cannot be negative")
+	}
+	if p.SentencePause < 0 || p.ParagraphPause < 0 {
+		return fmt.Errorf("pauses cannot be negative")
+	}
+	return nil
+}
+
+// textChunk is a piece of text that is synthesized on its own.
+type textChunk struct {
+	text string
+	// pauseAfter is the silence inserted after the chunk's audio.
+	pauseAfter time.Duration
+}
+
+// sentenceRules describes how a language's text is split into sentences.
+type sentenceRules struct {
+	// terminators end a sentence.
+	terminators string
+	// clauseBreaks are where a sentence that is too long is preferably split.
+	clauseBreaks string
+	// noSpaces is set for scripts that don't put spaces between words or sentences.
+	noSpaces bool
+}
+
+// defaultSentenceRules are used for languages without their own rules.
+var defaultSentenceRules = sentenceRules{
+	terminators:  ".!?…",
+	clauseBreaks: ",;:—",
+}
+
+// sentenceRulesByLanguage holds the punctuation rules of languages that differ from the default.
+var sentenceRulesByLanguage = map[model.Language]sentenceRules{
+	model.Chinese:  {terminators: "。！？.!?…", clauseBreaks: "，、；：,;:", noSpaces: true},
+	model.Japanese: {terminators: "。！？.!?…", clauseBreaks: "、，；：,;:", noSpaces: true},
+	model.Arabic:   {terminators: ".!?؟…", clauseBreaks: "،؛,;:"},
+	model.Persian:  {terminators: ".!?؟…", clauseBreaks: "،؛,;:"},
+	// Greek uses ";" (or the Greek question mark) as its question mark, and the ano teleia as a semicolon.
+	model.Greek:   {terminators: ".!;\u037e…", clauseBreaks: ",\u0387:—"},
+	model.Bengali: {terminators: "।.!?…", clauseBreaks: ",;:—"},
+}
+
+// rulesFor returns the sentence rules for a language.
+func rulesFor(lang model.Language) sentenceRules {
+	if rules, ok := sentenceRulesByLanguage[lang]; ok {
+		return rules
+	}
+	return defaultSentenceRules
+}
+
+// closingPunctuation may follow a sentence terminator and still belongs to the sentence.
+const closingPunctuation = "\"'”’)]»」』）"
+
+// paragraphBreak matches the blank lines between paragraphs.
+var paragraphBreak = regexp.MustCompile(`\n[ \t]*\n`)
+
+// splitsText reports whether text is split into chunks before it's synthesized:
+// when a chunk policy was set, or the worker keeps the model loaded between chunks.
+func (t TTS) splitsText() bool {
+	_, ok := t.backend.(*worker)
+	return t.chunkingSet || ok
+}
+
+// textChunks returns the chunks text is synthesized in, or nil if it's synthesized in one go.
+func (t TTS) textChunks(text string) []textChunk {
+	if !t.splitsText() {
+		return nil
+	}
+	if chunks := t.splitText(text); len(chunks) > 1 {
+		return chunks
+	}
+	return nil
+}
+
+// splitText splits text into chunks according to the chunk policy and the model's language.
+func (t TTS) splitText(text string) []textChunk {
+	rules := rulesFor(t.model.CurrentLanguage)
+
+	var chunks []textChunk
+	for _, paragraph := range splitParagraphs(text) {
+		sentences := rules.splitSentences(paragraph)
+		if len(sentences) == 0 {
+			continue
+		}
+		if len(chunks) > 0 {
+			chunks[len(chunks)-1].pauseAfter = t.chunking.ParagraphPause
+		}
+		chunks = append(chunks, t.chunking.pack(sentences, rules)...)
+	}
+
+	if len(chunks) > 0 {
+		chunks[len(chunks)-1].pauseAfter = 0
+	}
+	return chunks
+}
+
+// pack joins sentences into chunks of up to MaxChars characters.
+func (p ChunkPolicy) pack(sentences []string, rules sentenceRules) []textChunk {
+	var chunks []textChunk
+	for _, sentence := range sentences {
+		pieces := rules.splitLong(sentence, p.MaxChars)
+		for i, piece := range pieces {
+			n := len(chunks)
+			if n > 0 && p.SentencePause == 0 && p.fits(rules.join(chunks[n-1].text, piece)) {
+				chunks[n-1].text = rules.join(chunks[n-1].text, piece)
+				continue
+			}
+
+			// Pieces of a long sentence are stitched back together without a pause.
+			var pause time.Duration
+			if i == len(pieces)-1 {
+				pause = p.SentencePause
+			}
+			chunks = append(chunks, textChunk{text: piece, pauseAfter: pause})
+		}
+	}
+	return chunks
+}
+
+// fits reports whether text is short enough to be a single chunk.
+func (p ChunkPolicy) fits(text string) bool {
+	return p.MaxChars == 0 || utf8.RuneCountInString(text) <= p.MaxChars
+}
+
+// splitParagraphs splits text on blank lines and collapses the whitespace within each paragraph.
+func splitParagraphs(text string) []string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+
+	var paragraphs []string
+	for _, p := range paragraphBreak.Split(text, -1) {
+		if p = strings.Join(strings.Fields(p), " "); p != "" {
+			paragraphs = append(paragraphs, p)
+		}
+	}
+	return paragraphs
+}
+
+// splitSentences splits a paragraph into sentences.
+// A terminator followed by a space only ends a sentence if the next word doesn't start in lowercase,
+// which keeps abbreviations like "e.g. this" together.
+func (r sentenceRules) splitSentences(paragraph string) []string {
+	runes := []rune(paragraph)
+
+	var sentences []string
+	start := 0
+	for i := 0; i < len(runes); i++ {
+		if !strings.ContainsRune(r.terminators, runes[i]) {
+			continue
+		}
+
+		end := i + 1
+		for end < len(runes) && strings.ContainsRune(r.terminators+closingPunctuation, runes[end]) {
+			end++
+		}
+
+		if r.isBoundary(runes, runes[i], end) {
+			if s := strings.TrimSpace(string(runes[start:end])); s != "" {
+				sentences = append(sentences, s)
+			}
+			start = end
+		}
+		i = end - 1
+	}
+
+	if s := strings.TrimSpace(string(runes[start:])); s != "" {
+		sentences = append(sentences, s)
+	}
+	return sentences
+}
+
+// isBoundary reports whether a sentence ending with terminator ends at runes[end].
+func (r sentenceRules) isBoundary(runes []rune, terminator rune, end int) bool {
+	if end == len(runes) {
+		return true
+	}
+	if !unicode.IsSpace(runes[end]) {
+		// Scripts without spaces end sentences with full-width punctuation directly followed by the next one.
+		// Elsewhere, a terminator not followed by a space is part of something like "3.14".
+		return r.noSpaces && terminator >= utf8.RuneSelf
+	}
+	for _, next := range runes[end:] {
+		if !unicode.IsSpace(next) {
+			return !unicode.IsLower(next)
+		}
+	}
+	return true
+}
+
+// splitLong splits a sentence longer than max characters at clause breaks, between words,
+// or if neither is possible, at max characters.
+func (r sentenceRules) splitLong(sentence string, max int) []string {
+	if max <= 0 {
+		return []string{sentence}
+	}
+
+	var pieces []string
+	runes := []rune(sentence)
+	for len(runes) > max {
+		cut := r.breakPoint(runes, max)
+		if piece := strings.TrimSpace(string(runes[:cut])); piece != "" {
+			pieces = append(pieces, piece)
+		}
+		runes = []rune(strings.TrimSpace(string(runes[cut:])))
+	}
+	if len(runes) > 0 {
+		pieces = append(pieces, string(runes))
+	}
+	return pieces
+}
+
+// breakPoint returns where to split runes so the first piece has at most max characters.
+func (r sentenceRules) breakPoint(runes []rune, max int) int {
+	for i := max - 1; i > 0; i-- {
+		if strings.ContainsRune(r.clauseBreaks, runes[i]) {
+			return i + 1
+		}
+	}
+	for i := max; i > 0; i-- {
+		if unicode.IsSpace(runes[i]) {
+			return i
+		}
+	}
+	return max
+}
+
+// join appends a sentence to a chunk.
+func (r sentenceRules) join(chunk, sentence string) string {
+	if r.noSpaces {
+		return chunk + sentence
+	}
+	return chunk + " " + sentence
+}
+
+// synthesizeChunks synthesizes each chunk separately and concatenates the audio, with the chunk's pause after it.
+// The returned attempts are the total across all chunks.
+func (t TTS) synthesizeChunks(ctx context.Context, chunks []textChunk) ([]byte, attemptStats, int, error) {
+	var clips []*audio.Clip
+	var total attemptStats
+	var attempts int
+	for i, chunk := range chunks {
+		t.log().LogAttrs(ctx, slog.LevelDebug, "synthesizing chunk",
+			slog.Int("chunk", i+1),
+			slog.Int("chunks", len(chunks)),
+			t.textAttr(chunk.text),
+		)
+
+		var wav []byte
+		var stats attemptStats
+		n, err := t.retry(ctx, func() (err error) {
+			wav, stats, err = t.runPipe(ctx, chunk.text)
+			return err
+		})
+		attempts += n
+		if err != nil {
+			return nil, attemptStats{}, attempts, fmt.Errorf("failed to synthesize chunk %d of %d: %w", i+1, len(chunks), err)
+		}
+
+		clip, err := audio.DecodeBytes(wav)
+		if err != nil {
+			return nil, attemptStats{}, attempts, fmt.Errorf("failed to decode chunk %d of %d: %w", i+1, len(chunks), err)
+		}
+		clips = append(clips, clip)
+		if chunk.pauseAfter > 0 {
+			clips = append(clips, audio.Silence(clip.SampleRate, clip.Channels, clip.Format, chunk.pauseAfter))
+		}
+		total.processingTime += stats.processingTime
+	}
+
+	joined, err := audio.Concat(clips...)
+	if err != nil {
+		return nil, attemptStats{}, attempts, fmt.Errorf("failed to stitch chunks: %w", err)
+	}
+	wav, err := joined.Bytes()
+	if err != nil {
+		return nil, attemptStats{}, attempts, fmt.Errorf("failed to encode stitched audio: %w", err)
+	}
+
+	if d := joined.Duration(); d > 0 {
+		total.realTimeFactor = total.processingTime.Seconds() / d.Seconds()
+	}
+	return wav, total, attempts, nil
+}
@@ -0,0 +1,193 @@
+package coqui
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pixellini/go-coqui/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitText(t *testing.T) {
+	noLimit := ChunkPolicy{}
+
+	tests := []struct {
+		name     string
+		policy   ChunkPolicy
+		language model.Language
+		text     string
+		expected []textChunk
+	}{
+		{
+			name:     "Short text is a single chunk",
+			policy:   DefaultChunkPolicy(),
+			text:     "Hello world. How are you?",
+			expected: []textChunk{{text: "Hello world. How are you?"}},
+		},
+		{
+			name:   "Paragraphs are separate chunks",
+			policy: ChunkPolicy{ParagraphPause: time.Second},
+			text:   "First paragraph.\nStill the first.\r\n\r\nSecond paragraph.",
+			expected: []textChunk{
+				{text: "First paragraph. Still the first.", pauseAfter: time.Second},
+				{text: "Second paragraph."},
+			},
+		},
+		{
+			name:   "Sentence pause splits every sentence",
+			policy: ChunkPolicy{SentencePause: 200 * time.Millisecond},
+			text:   `"Is it?" she asked. Yes! It costs 3.50 today, e.g. now.`,
+			expected: []textChunk{
+				{text: `"Is it?" she asked.`, pauseAfter: 200 * time.Millisecond},
+				{text: "Yes!", pauseAfter: 200 * time.Millisecond},
+				{text: "It costs 3.50 today, e.g. now."},
+			},
+		},
+		{
+			name:   "Sentences are packed up to max chars",
+			policy: ChunkPolicy{MaxChars: 30},
+			text:   "One sentence here. Another one here. And a third.",
+			expected: []textChunk{
+				{text: "One sentence here."},
+				{text: "Another one here. And a third."},
+			},
+		},
+		{
+			name:     "Chinese punctuation",
+			policy:   ChunkPolicy{SentencePause: time.Second},
+			language: model.Chinese,
+			text:     "你好。今天天气很好！",
+			expected: []textChunk{
+				{text: "你好。", pauseAfter: time.Second},
+				{text: "今天天气很好！"},
+			},
+		},
+		{
+			name:     "Arabic question mark",
+			policy:   ChunkPolicy{SentencePause: time.Second},
+			language: model.Arabic,
+			text:     "كيف حالك؟ أنا بخير.",
+			expected: []textChunk{
+				{text: "كيف حالك؟", pauseAfter: time.Second},
+				{text: "أنا بخير."},
+			},
+		},
+		{
+			name:     "Greek question mark",
+			policy:   ChunkPolicy{SentencePause: time.Second},
+			language: model.Greek,
+			text:     "Τι κάνεις; Καλά.",
+			expected: []textChunk{
+				{text: "Τι κάνεις;", pauseAfter: time.Second},
+				{text: "Καλά."},
+			},
+		},
+		{
+			name:     "Whitespace only",
+			policy:   noLimit,
+			text:     " \n\n \t",
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			language := tt.language
+			if language == "" {
+				language = model.English
+			}
+			coqui := TTS{chunking: tt.policy}
+			coqui.model.CurrentLanguage = language
+
+			assert.Equal(t, tt.expected, coqui.splitText(tt.text))
+		})
+	}
+}
+
+func TestSplitLong(t *testing.T) {
+	tests := []struct {
+		name     string
+		sentence string
+		max      int
+		expected []string
+	}{
+		{name: "Fits", sentence: "Short enough.", max: 20, expected: []string{"Short enough."}},
+		{name: "No limit", sentence: "Short enough.", max: 0, expected: []string{"Short enough."}},
+		{name: "Clause break", sentence: "First part, second part here.", max: 20, expected: []string{"First part,", "second part here."}},
+		{name: "Between words", sentence: "one two three four five", max: 10, expected: []string{"one two", "three four", "five"}},
+		{name: "Hard cut", sentence: "abcdefghij", max: 4, expected: []string{"abcd", "efgh", "ij"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, defaultSentenceRules.splitLong(tt.sentence, tt.max))
+		})
+	}
+}
+
+func TestChunkPolicy_Validate(t *testing.T) {
+	assert.NoError(t, DefaultChunkPolicy().Validate())
+	assert.NoError(t, ChunkPolicy{}.Validate())
+	assert.Error(t, ChunkPolicy{MaxChars: -1}.Validate())
+	assert.Error(t, ChunkPolicy{ParagraphPause: -time.Second}.Validate())
+}
+
+func TestSynthesize_Chunked(t *testing.T) {
+	coqui, runner := newTestTTS(t, WithChunkPolicy(ChunkPolicy{ParagraphPause: 100 * time.Millisecond}))
+	runner.respond = func(cmd Command) (Output, error) {
+		return Output{Stdout: fakeWav(22050, 1, 2205), Stderr: []byte(" > Processing time: 0.25\n")}, nil
+	}
+
+	result, err := coqui.Synthesize("First paragraph.\n\nSecond paragraph.", "chunked.wav")
+	require.NoError(t, err)
+
+	calls := runner.calls()
+	require.Len(t, calls, 2)
+	assert.Equal(t, "First paragraph.", argValue(calls[0].Args, argText))
+	assert.Equal(t, "Second paragraph.", argValue(calls[1].Args, argText))
+
+	assert.Equal(t, 2, result.Attempts)
+	assert.Equal(t, 500*time.Millisecond, result.ProcessingTime)
+	assert.Equal(t, 300*time.Millisecond, result.Duration, "Both chunks and the pause between them")
+	assert.FileExists(t, result.OutputPath)
+}
+
+func TestSynthesizeBytes_ChunkFailure(t *testing.T) {
+	coqui, runner := newTestTTS(t, WithMaxRetries(1), WithChunkPolicy(DefaultChunkPolicy()))
+	runner.respond = func(cmd Command) (Output, error) {
+		if strings.HasPrefix(argValue(cmd.Args, argText), "Second") {
+			return Output{Stderr: []byte("Model `x` not found in the model list.")}, assert.AnError
+		}
+		return Output{Stdout: fakeWav(22050, 1, 10)}, nil
+	}
+
+	_, err := coqui.SynthesizeBytes(context.Background(), "First.\n\nSecond.")
+	assert.ErrorIs(t, err, ErrModelNotFound)
+	assert.ErrorContains(t, err, "chunk 2 of 2")
+}
+
+func TestSynthesize_NotChunkedByDefault(t *testing.T) {
+	coqui, runner := newTestTTS(t)
+	respondWithWav(runner, fakeWav(22050, 1, 10))
+
+	_, err := coqui.SynthesizeBytes(context.Background(), "First paragraph.\n\nSecond paragraph.")
+	require.NoError(t, err)
+
+	calls := runner.calls()
+	require.Len(t, calls, 1, "The tts command should not reload the model for every chunk")
+	assert.Equal(t, "First paragraph.\n\nSecond paragraph.", argValue(calls[0].Args, argText))
+}
+
+func TestTextChunks(t *testing.T) {
+	text := "First paragraph.\n\nSecond paragraph."
+
+	cli, _ := newTestTTS(t)
+	assert.Nil(t, cli.textChunks(text))
+
+	worker, _ := newTestTTS(t, WithWorkerCommand("fake-worker"))
+	assert.Len(t, worker.textChunks(text), 2, "The worker keeps the model loaded, so it splits text by default")
+	assert.Nil(t, worker.textChunks("Short."))
+}
@@ -0,0 +1,176 @@
+package coqui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/pixellini/go-coqui/model"
+	"github.com/pixellini/go-coqui/models/voiceconversion"
+)
+
+// Convert converts the speech in sourceWav into the voice of the target recordings and saves it to outputPath,
+// using the model set with WithVoiceConversion, or FreeVC24 if none is set.
+// If targetWavs is empty, the targets set with WithVoiceConversion are used.
+// Like Synthesize, outputPath is in the output directory, its extension picks the output format,
+// and the configured post-processing is applied.
+// Returns an error if the output file already exists.
+// Voice conversion runs the tts command, so it isn't supported with WithWorker or WithServer.
+func (t TTS) Convert(ctx context.Context, sourceWav string, targetWavs []string, outputPath string) (*SynthesisResult, error) {
+	if sourceWav == "" {
+		return nil, errors.New("source audio path cannot be empty")
+	}
+	if err := t.requireCommand("voice conversion"); err != nil {
+		return nil, err
+	}
+	if len(targetWavs) == 0 {
+		targetWavs = t.vcTargets
+	}
+	if len(targetWavs) == 0 {
+		return nil, errors.New("at least one target recording is required")
+	}
+	if err := checkWavs(append([]string{sourceWav}, targetWavs...)); err != nil {
+		return nil, err
+	}
+
+	out, cleanup, err := t.prepareOutput(outputPath)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	vc := t.conversionModel()
+	t.log().LogAttrs(ctx, slog.LevelDebug, "converting voice",
+		slog.String("model", vc.Name()),
+		slog.String("source", sourceWav),
+		slog.String("output_path", out.path),
+	)
+
+	startedAt := time.Now()
+	stats, attempts, err := t.convert(ctx, sourceWav, targetWavs, out.wavPath)
+	if err != nil {
+		return nil, err
+	}
+
+	clip, err := t.finishFile(ctx, out.wavPath, out.path, out.format)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := t.newResult(out.path, out.format, clip, stats, attempts, startedAt)
+	if err != nil {
+		return nil, err
+	}
+	result.Model, result.Vocoder, result.VoiceConversion = vc.Name(), "", vc.Name()
+
+	t.log().LogAttrs(ctx, slog.LevelInfo, "voice conversion complete",
+		slog.String("model", result.Model),
+		slog.Int("attempts", result.Attempts),
+		slog.Duration("duration", result.Elapsed),
+		slog.String("output_path", result.OutputPath),
+	)
+	return result, nil
+}
+
+// converting reports whether synthesized speech is converted into another voice.
+func (t TTS) converting() bool {
+	return len(t.vcTargets) > 0
+}
+
+// conversionModel returns the voice conversion model to use.
+func (t TTS) conversionModel() voiceconversion.Model {
+	if t.voiceConversion.Category == "" {
+		return voiceconversion.PresetVCTKFreeVC24
+	}
+	return t.voiceConversion
+}
+
+// convert runs the voice conversion command, retrying on failure like synthesis.
+func (t TTS) convert(ctx context.Context, sourceWav string, targetWavs []string, outputPath string) (attemptStats, int, error) {
+	var stats attemptStats
+	attempts, err := t.retry(ctx, func() error {
+		out, err := t.runCommand(ctx, conversionArgs(t, sourceWav, targetWavs, outputPath))
+		if err != nil {
+			t.log().LogAttrs(ctx, slog.LevelDebug, "voice conversion command failed", t.outputAttr(out.Combined()))
+			return newCommandError(out, err)
+		}
+		stats = parseStats(out.Combined())
+		return nil
+	})
+	return stats, attempts, err
+}
+
+// convertInPlace converts the synthesized speech in wavPath into the voice of the configured targets.
+func (t TTS) convertInPlace(ctx context.Context, wavPath string) (attemptStats, int, error) {
+	tmp, err := os.CreateTemp("", "go-coqui-vc-*.wav")
+	if err != nil {
+		return attemptStats{}, 0, fmt.Errorf("failed to create temporary conversion file: %w", err)
+	}
+	tmp.Close()
+	defer os.Remove(tmp.Name())
+
+	stats, attempts, err := t.convert(ctx, wavPath, t.vcTargets, tmp.Name())
+	if err != nil {
+		return attemptStats{}, attempts, err
+	}
+
+	wav, err := os.ReadFile(tmp.Name())
+	if err != nil {
+		return attemptStats{}, attempts, fmt.Errorf("failed to read converted audio: %w", err)
+	}
+	if err := os.WriteFile(wavPath, wav, 0644); err != nil {
+		return attemptStats{}, attempts, fmt.Errorf("failed to write converted audio: %w", err)
+	}
+	return stats, attempts, nil
+}
+
+// convertBytes converts synthesized WAV audio into the voice of the configured targets.
+// Coqui only converts files, so the audio goes through temporary files outside the output directory.
+func (t TTS) convertBytes(ctx context.Context, wav []byte) ([]byte, error) {
+	tmp, err := os.CreateTemp("", "go-coqui-vc-*.wav")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary conversion file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	_, err = tmp.Write(wav)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to write temporary conversion file: %w", err)
+	}
+
+	if _, _, err := t.convertInPlace(ctx, tmp.Name()); err != nil {
+		return nil, err
+	}
+	return os.ReadFile(tmp.Name())
+}
+
+// conversionArgs returns the arguments that convert sourceWav into the voice of targetWavs.
+func conversionArgs(t TTS, sourceWav string, targetWavs []string, outputPath string) []string {
+	device := t.resolveDevice()
+	args := []string{
+		argDevice, device.String(),
+		argModelName, t.conversionModel().Name(),
+	}
+	if device == model.DeviceCUDA {
+		args = append(args, argUseCuda, "true")
+	}
+
+	args = append(args, argSourceWav, sourceWav, argTargetWav)
+	args = append(args, targetWavs...)
+	return append(args, argOutPath, outputPath)
+}
+
+// checkWavs verifies that the audio files exist.
+func checkWavs(paths []string) error {
+	for _, path := range paths {
+		if _, err := os.Stat(path); err != nil {
+			return fmt.Errorf("audio file %s: %w", path, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package coqui
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pixellini/go-coqui/models/tts"
+	"github.com/pixellini/go-coqui/models/voiceconversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeTargets writes placeholder target recordings and returns their paths.
+func writeTargets(t *testing.T, names ...string) []string {
+	t.Helper()
+	dir := t.TempDir()
+	var paths []string
+	for _, name := range names {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, toneWav(t, 16000, 0.5, 10*time.Millisecond), 0644))
+		paths = append(paths, path)
+	}
+	return paths
+}
+
+func TestConvert(t *testing.T) {
+	coqui, runner := newTestTTS(t)
+	respondWithWav(runner, toneWav(t, 24000, 0.5, 300*time.Millisecond))
+	source := writeTargets(t, "source.wav")[0]
+	targets := writeTargets(t, "target1.wav", "target2.wav")
+
+	result, err := coqui.Convert(context.Background(), source, targets, "converted.flac")
+	require.NoError(t, err)
+	assert.Equal(t, "voice_conversion_models/multilingual/vctk/freevc24", result.Model, "FreeVC24 should be used by default")
+	assert.Equal(t, result.Model, result.VoiceConversion)
+	assert.Equal(t, FormatFLAC, result.Format)
+	assert.Equal(t, 300*time.Millisecond, result.Duration)
+
+	calls := runner.calls()
+	require.Len(t, calls, 1)
+	args := calls[0].Args
+	assert.Equal(t, "voice_conversion_models/multilingual/vctk/freevc24", argValue(args, argModelName))
+	assert.Equal(t, source, argValue(args, argSourceWav))
+	assert.Subset(t, args, append([]string{argTargetWav}, targets...))
+	assert.NotContains(t, args, argText)
+}
+
+func TestConvert_ConfiguredModel(t *testing.T) {
+	targets := writeTargets(t, "target.wav")
+	coqui, runner := newTestTTS(t, WithVoiceConversion(voiceconversion.PresetMultidataOpenVoiceV2, targets...))
+	respondWithWav(runner, toneWav(t, 24000, 0.5, 100*time.Millisecond))
+	source := writeTargets(t, "source.wav")[0]
+
+	result, err := coqui.Convert(context.Background(), source, nil, "converted.wav")
+	require.NoError(t, err)
+	assert.Equal(t, "voice_conversion_models/multilingual/multi-dataset/openvoice_v2", result.Model)
+	assert.Equal(t, targets[0], argValue(runner.calls()[0].Args, argTargetWav), "Configured targets should be used")
+}
+
+func TestConvert_Invalid(t *testing.T) {
+	coqui, runner := newTestTTS(t)
+	source := writeTargets(t, "source.wav")[0]
+
+	_, err := coqui.Convert(context.Background(), source, nil, "converted.wav")
+	assert.Error(t, err, "A target is required")
+
+	_, err = coqui.Convert(context.Background(), source, []string{"missing.wav"}, "converted.wav")
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	_, err = coqui.Convert(context.Background(), "", []string{source}, "converted.wav")
+	assert.Error(t, err)
+	assert.Empty(t, runner.calls())
+}
+
+func TestSynthesize_VoiceConversion(t *testing.T) {
+	targets := writeTargets(t, "target.wav")
+	coqui, runner := newTestTTS(t,
+		WithModelId(tts.PresetVITSLJSpeech),
+		WithVoiceConversion(voiceconversion.PresetVCTKFreeVC24, targets...),
+	)
+	respondWithWav(runner, toneWav(t, 22050, 0.5, 200*time.Millisecond))
+
+	result, err := coqui.Synthesize("Hello world", "hello.wav")
+	require.NoError(t, err)
+	assert.Equal(t, "tts_models/en/ljspeech/vits", result.Model)
+	assert.Equal(t, "voice_conversion_models/multilingual/vctk/freevc24", result.VoiceConversion)
+	assert.Equal(t, 2, result.Attempts, "Synthesis and conversion should both be counted")
+
+	calls := runner.calls()
+	require.Len(t, calls, 2)
+	assert.Equal(t, argValue(calls[0].Args, argOutPath), argValue(calls[1].Args, argSourceWav), "Synthesized speech should be converted")
+	assert.Equal(t, targets[0], argValue(calls[1].Args, argTargetWav))
+	_, err = os.Stat(argValue(calls[1].Args, argOutPath))
+	assert.ErrorIs(t, err, os.ErrNotExist, "The temporary conversion file should be removed")
+}
+
+func TestSynthesizeBytes_VoiceConversion(t *testing.T) {
+	targets := writeTargets(t, "target.wav")
+	coqui, runner := newTestTTS(t, WithVoiceConversion(voiceconversion.PresetVCTKFreeVC24, targets...))
+	converted := toneWav(t, 16000, 0.25, 100*time.Millisecond)
+	runner.respond = func(cmd Command) (Output, error) {
+		if argValue(cmd.Args, argSourceWav) != "" {
+			return Output{}, os.WriteFile(argValue(cmd.Args, argOutPath), converted, 0644)
+		}
+		return Output{Stdout: toneWav(t, 24000, 0.5, 100*time.Millisecond)}, nil
+	}
+
+	wav, err := coqui.SynthesizeBytes(context.Background(), "Hello world")
+	require.NoError(t, err)
+	assert.Equal(t, converted, wav)
+	assert.Len(t, runner.calls(), 2)
+}
+
+func TestConvert_Backend(t *testing.T) {
+	targets := writeTargets(t, "source.wav", "target.wav")
+	coqui, runner := newTestTTS(t,
+		WithServer("http://localhost:5002"),
+		WithVoiceConversion(voiceconversion.PresetVCTKFreeVC24, targets[1]),
+	)
+
+	_, err := coqui.Convert(context.Background(), targets[0], nil, "converted.wav")
+	assert.ErrorIs(t, err, ErrNotSupported)
+	_, err = coqui.Synthesize("Hello world", "hello.wav")
+	assert.ErrorIs(t, err, ErrNotSupported, "Chained conversion should be rejected before synthesizing")
+	_, err = coqui.SynthesizeBytes(context.Background(), "Hello world")
+	assert.ErrorIs(t, err, ErrNotSupported)
+	assert.Empty(t, runner.calls(), "The CLI should not run when a backend is configured")
+	assert.NoFileExists(t, filepath.Join(coqui.CurrentOutputDir(), "converted.wav"))
+}
+
+func TestWithVoiceConversion_Invalid(t *testing.T) {
+	_, err := New(WithRunner(&fakeRunner{}), WithVoiceConversion(tts.PresetXTTSv2))
+	assert.Error(t, err, "A TTS model is not a voice conversion model")
+
+	_, err = New(WithRunner(&fakeRunner{}), WithVoiceConversion(voiceconversion.PresetVCTKFreeVC24, "missing.wav"))
+	assert.Error(t, err)
+}
@@ -4,14 +4,22 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"log"
+	"io"
+	"log/slog"
+	"net/http"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
+	"strings"
+	"time"
 
+	"github.com/pixellini/go-coqui/audio"
 	"github.com/pixellini/go-coqui/model"
 	"github.com/pixellini/go-coqui/models/tts"
 	"github.com/pixellini/go-coqui/models/vocoder"
+	"github.com/pixellini/go-coqui/models/voiceconversion"
+	"github.com/pixellini/go-coqui/voices"
 )
 
 // TTS represents a text-to-speech synthesis engine.
@@ -28,9 +36,10 @@ type TTS struct {
 	// If not set, the default vocoder for the model will be used.
 	// This is useful for advanced configurations where a specific vocoder is desired.
 	vocoder vocoder.Model
-	// speakerSample is the path to the speaker sample file (XTTS only).
+	// speakerSamples are the paths to the speaker sample files (XTTS only).
+	// Coqui averages the voice of every sample, so several short, clean clips clone better than one long one.
 	// Should be a clear audio sample of the desired voice (1-3 minutes recommended).
-	speakerSample string
+	speakerSamples []string
 	// speakerIdx is the speaker index identifier (VITS only).
 	// Use speaker IDs like "p225", "p287", ett. from the VCTK dataset.
 	speakerIdx string
@@ -43,6 +52,77 @@ type TTS struct {
 	// maxRetries is the maximum number of synthesis attempts on failure.
 	// Recommended range is 1-5; higher values increase reliability but slow down failure recovery.
 	maxRetries int
+	// retryPolicy controls the backoff between attempts and which failures are retried.
+	retryPolicy RetryPolicy
+	// runner executes the Coqui TTS commands.
+	// Defaults to ExecRunner, which spawns the "tts" executable.
+	runner Runner
+	// executable is the path or name of the Coqui TTS executable.
+	// Defaults to "tts", resolved from PATH.
+	executable string
+	// python is the Python interpreter used to run "python -m TTS.bin.synthesize".
+	// If set, this is used instead of the executable.
+	// Useful when Coqui lives in a virtualenv that isn't on PATH.
+	python string
+	// workingDir is the working directory for the Coqui TTS process.
+	// If empty, the current working directory is used.
+	workingDir string
+	// env holds extra environment variables for the Coqui TTS process in "KEY=value" form.
+	env []string
+	// logger receives structured logs about synthesis.
+	// If nil, nothing is logged.
+	logger *slog.Logger
+	// logText includes the text being synthesized (and the command output, which echoes it) in logs.
+	// Disabled by default so user text doesn't leak into logs.
+	logText bool
+	// backend synthesizes audio instead of running the Coqui TTS command for every call.
+	// If nil, the command is run through the runner.
+	backend backend
+	// chunking controls how long text is split into chunks that are synthesized separately.
+	chunking ChunkPolicy
+	// chunkingSet is set once a chunk policy is configured, which turns chunking on without the worker.
+	chunkingSet bool
+	// trim controls how leading and trailing silence is trimmed from synthesized audio.
+	// The zero value leaves the silence in place.
+	trim audio.TrimOptions
+	// outputSampleRate is the sample rate synthesized audio is converted to.
+	// 0 keeps the model's sample rate.
+	outputSampleRate int
+	// outputChannels is the number of channels synthesized audio is converted to.
+	// 0 keeps the model's channels.
+	outputChannels int
+	// outputFormat is the format synthesized audio is encoded in.
+	// If empty, the format is taken from the output file extension.
+	outputFormat OutputFormat
+	// encoders holds encoders registered with WithEncoder, overriding the built-in ones.
+	encoders map[OutputFormat]Encoder
+	// loudness is the loudness synthesized audio is normalized to.
+	// The zero value leaves the loudness as the model produced it.
+	loudness audio.LoudnessTarget
+	// catalog caches the models offered by the installed Coqui TTS.
+	catalog *catalogCache
+	// ids caches the speakers and languages listed for each model.
+	ids *idCache
+	// voiceConversion is the model synthesized speech is converted with, into the voice of vcTargets.
+	voiceConversion voiceconversion.Model
+	// vcTargets are recordings of the voice synthesized speech is converted into.
+	// If empty, synthesized speech isn't converted.
+	vcTargets []string
+	// temperature controls how varied the synthesized voice sounds.
+	// 0 uses the model's default. Only the worker can pass it to Coqui.
+	temperature float64
+	// speed is the speaking rate, where 1 is normal speed.
+	// 0 uses the model's default. Only the worker can pass it to Coqui.
+	speed float64
+	// voices is the library WithVoice looks voices up in.
+	voices *voices.Library
+	// voice is the name of the voice profile last applied.
+	voice string
+	// samplePolicy is what ValidateSpeakerSample checks speaker samples against.
+	samplePolicy SamplePolicy
+	// httpClient sends requests to the tts-server.
+	// If nil, http.DefaultClient is used, which has no timeout.
+	httpClient *http.Client
 }
 
 const (
@@ -50,16 +130,26 @@ const (
 	defaultOutputDir  = "./dist/"
 	defaultDevice     = model.DeviceAuto
 	defaultMaxRetries = 3
+	defaultExecutable = "tts"
+	// pythonModule is the Python module behind the "tts" executable.
+	pythonModule = "TTS.bin.synthesize"
 )
 
 // New creates a new TTS instance with the specified configuration options.
 func New(options ...Option) (*TTS, error) {
 	// Build the config, apply the defaults
 	tts := &TTS{
-		model:      tts.PresetXTTSv2,
-		outputDir:  defaultOutputDir,
-		device:     defaultDevice,
-		maxRetries: defaultMaxRetries,
+		model:        tts.PresetXTTSv2,
+		outputDir:    defaultOutputDir,
+		device:       defaultDevice,
+		maxRetries:   defaultMaxRetries,
+		retryPolicy:  DefaultRetryPolicy(),
+		chunking:     DefaultChunkPolicy(),
+		samplePolicy: DefaultSamplePolicy(),
+		runner:       ExecRunner{},
+		executable:   defaultExecutable,
+		catalog:      &catalogCache{},
+		ids:          &idCache{speakers: map[string][]string{}, languages: map[string][]string{}},
 	}
 
 	for _, option := range options {
@@ -69,6 +159,14 @@ func New(options ...Option) (*TTS, error) {
 		}
 	}
 
+	// Only the exec runner spawns a real process, custom runners may not need the executable at all.
+	// Backends check for what they need themselves when they're first used.
+	if _, ok := tts.runner.(ExecRunner); ok && tts.backend == nil {
+		if err := tts.checkExecutable(); err != nil {
+			return nil, fmt.Errorf("failed to create TTS instance: %w", err)
+		}
+	}
+
 	if tts.model.DefaultLanguage != "" && tts.model.CurrentLanguage == "" {
 		tts.model.CurrentLanguage = defaultLanguage
 	}
@@ -121,14 +219,14 @@ func (t *TTS) Configure(options ...Option) {
 
 // Synthesize converts text to speech and saves it to the specified output file.
 // This is a convenience method that uses context.Background().
-func (t TTS) Synthesize(text, outputPath string) ([]byte, error) {
+func (t TTS) Synthesize(text, outputPath string) (*SynthesisResult, error) {
 	return t.SynthesizeContext(context.Background(), text, outputPath)
 }
 
 // SynthesizeContext converts text to speech with context support for cancellation.
-// Supports automatic retries on failure and returns the command output on success.
+// Supports automatic retries on failure and returns a description of the generated audio on success.
 // Returns an error if the output file already exists.
-func (t TTS) SynthesizeContext(ctx context.Context, text, outputPath string) ([]byte, error) {
+func (t TTS) SynthesizeContext(ctx context.Context, text, outputPath string) (*SynthesisResult, error) {
 	if text == "" {
 		return nil, errors.New("text cannot be empty")
 	}
@@ -136,13 +234,23 @@ func (t TTS) SynthesizeContext(ctx context.Context, text, outputPath string) ([]
 	return t.synthesize(ctx, text, outputPath)
 }
 
+// SynthesizeAs converts text to speech and saves it to the specified output file in format,
+// whatever the output file extension or the format set with WithOutputFormat.
+// Use it to deliver the same prompt in several formats, e.g. FormatMuLaw for an IVR and FormatMP3 for the web.
+func (t TTS) SynthesizeAs(ctx context.Context, text, outputPath string, format OutputFormat) (*SynthesisResult, error) {
+	if err := t.SetCurrentOutputFormat(format); err != nil {
+		return nil, err
+	}
+	return t.SynthesizeContext(ctx, text, outputPath)
+}
+
 // SynthesizeFromFile converts text from a file to speech and saves it to the specified output file.
-func (t TTS) SynthesizeFromFile(filePath, outputPath string) ([]byte, error) {
+func (t TTS) SynthesizeFromFile(filePath, outputPath string) (*SynthesisResult, error) {
 	return t.SynthesizeFromFileContext(context.Background(), filePath, outputPath)
 }
 
 // SynthesizeFromFileContext converts text from a file to speech with context support.
-func (t TTS) SynthesizeFromFileContext(ctx context.Context, filePath, outputPath string) ([]byte, error) {
+func (t TTS) SynthesizeFromFileContext(ctx context.Context, filePath, outputPath string) (*SynthesisResult, error) {
 	if filePath == "" {
 		return nil, errors.New("file path cannot be empty")
 	}
@@ -154,77 +262,346 @@ func (t TTS) SynthesizeFromFileContext(ctx context.Context, filePath, outputPath
 	return t.synthesize(ctx, string(content), outputPath)
 }
 
+// SynthesizeBytes converts text to speech and returns the audio in memory.
+// The audio is WAV unless a format was set with WithOutputFormat.
+// Nothing is written to the output directory; the audio is streamed from Coqui's stdout using --pipe_out.
+func (t TTS) SynthesizeBytes(ctx context.Context, text string) ([]byte, error) {
+	if text == "" {
+		return nil, errors.New("text cannot be empty")
+	}
+	if err := t.checkSpeaker(t.speakerIdx); err != nil {
+		return nil, err
+	}
+	if t.converting() {
+		if err := t.requireCommand("voice conversion"); err != nil {
+			return nil, err
+		}
+	}
+
+	t.log().LogAttrs(ctx, slog.LevelDebug, "synthesizing",
+		slog.String("model", t.modelName()),
+		t.textAttr(text),
+	)
+
+	startedAt := time.Now()
+	var wav []byte
+	var attempts int
+	var err error
+	if chunks := t.textChunks(text); chunks != nil {
+		wav, _, attempts, err = t.synthesizeChunks(ctx, chunks)
+	} else {
+		attempts, err = t.retry(ctx, func() (err error) {
+			wav, _, err = t.runPipe(ctx, text)
+			return err
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+	if t.converting() {
+		if wav, err = t.convertBytes(ctx, wav); err != nil {
+			return nil, err
+		}
+	}
+	if wav, err = t.finishBytes(ctx, wav, t.bytesFormat()); err != nil {
+		return nil, err
+	}
+
+	t.log().LogAttrs(ctx, slog.LevelInfo, "synthesis complete",
+		slog.String("model", t.modelName()),
+		slog.Int("attempts", attempts),
+		slog.Duration("duration", time.Since(startedAt)),
+	)
+	return wav, nil
+}
+
+// SynthesizeBytesAs converts text to speech and returns the audio in memory in format,
+// whatever the format set with WithOutputFormat.
+func (t TTS) SynthesizeBytesAs(ctx context.Context, text string, format OutputFormat) ([]byte, error) {
+	if err := t.SetCurrentOutputFormat(format); err != nil {
+		return nil, err
+	}
+	return t.SynthesizeBytes(ctx, text)
+}
+
+// SynthesizeTo converts text to speech and writes the audio to w, in the same format as SynthesizeBytes.
+// Like SynthesizeBytes, nothing is written to the output directory.
+func (t TTS) SynthesizeTo(ctx context.Context, text string, w io.Writer) error {
+	wav, err := t.SynthesizeBytes(ctx, text)
+	if err != nil {
+		return err
+	}
+
+	if _, err := w.Write(wav); err != nil {
+		return fmt.Errorf("failed to write audio: %w", err)
+	}
+	return nil
+}
+
+// bytesFormat returns the format SynthesizeBytes returns audio in.
+func (t TTS) bytesFormat() OutputFormat {
+	if t.outputFormat == "" {
+		return FormatWAV
+	}
+	return t.outputFormat
+}
+
 // synthesize runs the TTS command to convert text to speech.
-func (t TTS) synthesize(ctx context.Context, text, outputPath string) ([]byte, error) {
+func (t TTS) synthesize(ctx context.Context, text, outputPath string) (*SynthesisResult, error) {
+	// Catch a bad speaker index before a long synthesis, if the model's speakers are already known.
+	if err := t.checkSpeaker(t.speakerIdx); err != nil {
+		return nil, err
+	}
+	// Check before synthesizing, rather than failing at the conversion once the synthesis is done.
+	if t.converting() {
+		if err := t.requireCommand("voice conversion"); err != nil {
+			return nil, err
+		}
+	}
+
+	out, cleanup, err := t.prepareOutput(outputPath)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	t.log().LogAttrs(ctx, slog.LevelDebug, "synthesizing",
+		slog.String("model", t.modelName()),
+		slog.String("output_path", out.path),
+		t.textAttr(text),
+	)
+
+	startedAt := time.Now()
+	var stats attemptStats
+	var attempts int
+	if chunks := t.textChunks(text); chunks != nil {
+		var wav []byte
+		wav, stats, attempts, err = t.synthesizeChunks(ctx, chunks)
+		if err == nil {
+			err = os.WriteFile(out.wavPath, wav, 0644)
+		}
+	} else {
+		attempts, err = t.retry(ctx, func() (err error) {
+			stats, err = t.run(ctx, text, out.wavPath)
+			return err
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if t.converting() {
+		vcStats, vcAttempts, err := t.convertInPlace(ctx, out.wavPath)
+		if err != nil {
+			return nil, err
+		}
+		stats.processingTime += vcStats.processingTime
+		attempts += vcAttempts
+	}
+
+	clip, err := t.finishFile(ctx, out.wavPath, out.path, out.format)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := t.newResult(out.path, out.format, clip, stats, attempts, startedAt)
+	if err != nil {
+		return nil, err
+	}
+	if t.converting() {
+		result.VoiceConversion = t.voiceConversion.Name()
+	}
+
+	t.log().LogAttrs(ctx, slog.LevelInfo, "synthesis complete",
+		slog.String("model", result.Model),
+		slog.Int("attempts", result.Attempts),
+		slog.Duration("duration", result.Elapsed),
+		slog.String("output_path", result.OutputPath),
+	)
+	return result, nil
+}
+
+// outputFile is where a synthesis or conversion writes its audio.
+type outputFile struct {
+	// path is the output path in the output directory.
+	path string
+	// wavPath is where Coqui writes WAV audio: path itself, or a temporary file when it's encoded to another format.
+	wavPath string
+	// format is the format the audio is delivered in.
+	format OutputFormat
+}
+
+// prepareOutput resolves outputPath in the output directory and picks the format it's written in.
+// The returned function removes the temporary WAV file, if one was needed.
+func (t TTS) prepareOutput(outputPath string) (outputFile, func(), error) {
 	// Create the dist directory if it doesn't exist
 	if err := os.MkdirAll(t.outputDir, 0755); err != nil {
-		return nil, fmt.Errorf("failed to create dist directory: %w", err)
+		return outputFile{}, nil, fmt.Errorf("failed to create dist directory: %w", err)
 	}
 
-	outputPath = t.outputDir + outputPath
+	out := outputFile{path: t.outputDir + outputPath, format: t.outputFormat}
+	if _, err := os.Stat(out.path); err == nil {
+		return outputFile{}, nil, fmt.Errorf("%w: %s", ErrOutputExists, out.path)
+	}
 
-	_, err := os.Stat(outputPath)
-	if err == nil {
-		return nil, fmt.Errorf("audio file already created")
+	if out.format == "" {
+		out.format = formatForPath(out.path)
+	}
+	if _, err := t.encoder(out.format); err != nil {
+		return outputFile{}, nil, err
 	}
 
-	var lastErr error
-	for attempt := 1; attempt <= t.maxRetries; attempt++ {
-		cmdOutput, err := t.run(ctx, text, outputPath)
-		if err == nil {
-			return cmdOutput, nil
-		}
+	// Coqui always writes WAV, so other formats are synthesized to a temporary file and encoded from it.
+	out.wavPath = out.path
+	if out.format == FormatWAV {
+		return out, func() {}, nil
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(out.path), ".go-coqui-*.wav")
+	if err != nil {
+		return outputFile{}, nil, fmt.Errorf("failed to create temporary output file: %w", err)
+	}
+	tmp.Close()
+	out.wavPath = tmp.Name()
+	return out, func() { os.Remove(out.wavPath) }, nil
+}
+
+// newResult describes the audio written to outputPath by a successful synthesis.
+func (t TTS) newResult(outputPath string, format OutputFormat, clip *audio.Clip, stats attemptStats, attempts int, startedAt time.Time) (*SynthesisResult, error) {
+	absPath, err := filepath.Abs(outputPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve output path: %w", err)
+	}
 
-		lastErr = err
-		log.Print(err)
-		log.Printf("TTS failed — (attempt %d/%d)\n", attempt, t.maxRetries)
+	result := &SynthesisResult{
+		OutputPath:     absPath,
+		Format:         format,
+		Duration:       clip.Duration(),
+		SampleRate:     clip.SampleRate,
+		Channels:       clip.Channels,
+		Attempts:       attempts,
+		StartedAt:      startedAt,
+		Elapsed:        time.Since(startedAt),
+		ProcessingTime: stats.processingTime,
+		RealTimeFactor: stats.realTimeFactor,
 	}
 
-	return nil, lastErr
+	// The tts-server doesn't say which model it ran, and it ignores the one configured here.
+	if _, ok := t.backend.(*serverBackend); !ok {
+		result.Model = t.modelName()
+		if t.vocoder.IsValid() {
+			result.Vocoder = t.VocoderName()
+		}
+		result.Device = t.resolveDevice()
+	}
+	return result, nil
+}
+
+// styled reports whether a temperature or speed is set.
+// The tts command and tts-server have no way to pass them to Coqui, only the worker does.
+func (t TTS) styled() bool {
+	return t.temperature != 0 || t.speed != 0
+}
+
+// supportsStyle reports whether the configured backend can pass a temperature and speed to Coqui.
+func (t TTS) supportsStyle() bool {
+	_, ok := t.backend.(*worker)
+	return ok
 }
 
 // run executes the Coqui TTS command with the specified text and output path.
 // This is an internal method that handles the actual subprocess execution.
-func (t TTS) run(ctx context.Context, text, outputPath string) ([]byte, error) {
+// If a backend is configured, it is used instead and the audio it returns is written to outputPath.
+func (t TTS) run(ctx context.Context, text, outputPath string) (attemptStats, error) {
+	if t.backend != nil {
+		wav, stats, err := t.backend.synthesize(ctx, t, text)
+		if err != nil {
+			return attemptStats{}, err
+		}
+		if err := os.WriteFile(outputPath, wav, 0644); err != nil {
+			return attemptStats{}, fmt.Errorf("failed to write audio: %w", err)
+		}
+		return stats, nil
+	}
+
+	if t.styled() {
+		return attemptStats{}, fmt.Errorf("%w: temperature and speed with the tts command, use WithWorker", ErrNotSupported)
+	}
+
 	args := toArgs(t)
 	args = append(args,
 		argText, text,
 		argOutPath, outputPath,
 	)
 
-	fmt.Printf("\nProcessing text: %q\n", text)
+	out, err := t.runCommand(ctx, args)
+	cmdOutput := out.Combined()
+	if err != nil {
+		t.log().LogAttrs(ctx, slog.LevelDebug, "TTS command failed", t.outputAttr(cmdOutput))
+		return attemptStats{}, newCommandError(out, err)
+	}
 
-	cmd := exec.CommandContext(ctx, "tts", args...)
+	return parseStats(cmdOutput), nil
+}
 
-	cmdOutput, err := cmd.CombinedOutput()
-	if err != nil {
-		fmt.Printf("\nTTS command failed with output: %s\n", cmdOutput)
-		return cmdOutput, fmt.Errorf("TTS command failed: %w", err)
+// runCommand executes the Coqui TTS executable with the given arguments through the configured Runner.
+func (t TTS) runCommand(ctx context.Context, args []string) (Output, error) {
+	runner := t.runner
+	if runner == nil {
+		runner = ExecRunner{}
+	}
+	return runner.Run(ctx, t.command(args))
+}
+
+// command builds the Command that runs Coqui TTS with the given arguments.
+// When a Python interpreter is configured, the CLI module is run with "python -m" instead of the executable.
+func (t TTS) command(args []string) Command {
+	name := t.executable
+	if name == "" {
+		name = defaultExecutable
+	}
+	if t.python != "" {
+		name = t.python
+		args = append([]string{"-m", pythonModule}, args...)
 	}
 
-	return cmdOutput, nil
+	return Command{
+		Name: name,
+		Args: args,
+		Dir:  t.workingDir,
+		Env:  t.env,
+	}
+}
+
+// checkExecutable verifies that the executable (or Python interpreter) Coqui TTS is run with exists.
+func (t TTS) checkExecutable() error {
+	name := t.command(nil).Name
+	if _, err := exec.LookPath(name); err != nil {
+		return &ExecutableNotFoundError{Name: name, Err: err}
+	}
+	return nil
 }
 
 // Name returns the full Coqui TTS model name to use.
 // Returns empty string if no model is configured.
 // Format: tts_models/{language}/{dataset}/{model}
-// For multilingual models, uses "multilingual" instead of specific language.
+// Models trained on every language use "multilingual" instead of a specific language.
 func (t TTS) Name() string {
-	// Use "multilingual" for models that support all languages.
-	// NOTE: This is currently a workaround so I can test the functionality.
-	// But this will break if the model supports multiple languages but is not "multilingual" as defined in the model name.
-	// TODO: Fix this to properly handle "multilingual" vs multilingual models.
-	language := t.model.CurrentLanguage.String()
-	if t.model.IsMultilingual() {
-		language = "multilingual"
+	return t.model.Name()
+}
+
+// modelName returns the model path for custom models, and the full model name otherwise.
+func (t TTS) modelName() string {
+	if t.modelPath != "" {
+		return t.modelPath
 	}
-	return fmt.Sprintf("%s/%s/%s/%s", t.model.Category, language, t.model.Dataset, t.model.Model)
+	return t.Name()
 }
 
 // VocoderName returns the full Coqui TTS vocoder name to use.
 // Format: vocoder_models/{language}/{dataset}/{model}
+// Vocoders trained on every language use "universal" instead of a specific language.
 func (t TTS) VocoderName() string {
-	return fmt.Sprintf("%s/%s/%s/%s", t.vocoder.Category, t.vocoder.DefaultLanguage, t.vocoder.Dataset, t.vocoder.Model)
+	return t.vocoder.Name()
 }
 
 // CurrentModel returns the Model being used for synthesis.
@@ -243,8 +620,17 @@ func (t TTS) CurrentModelLanguage() model.Language {
 }
 
 // CurrentSpeakerSample returns the path to the speaker sample file.
+// If several samples are set, it returns the first; use CurrentSpeakerSamples for all of them.
 func (t TTS) CurrentSpeakerSample() string {
-	return t.speakerSample
+	if len(t.speakerSamples) == 0 {
+		return ""
+	}
+	return t.speakerSamples[0]
+}
+
+// CurrentSpeakerSamples returns the paths to the speaker sample files.
+func (t TTS) CurrentSpeakerSamples() []string {
+	return t.speakerSamples
 }
 
 // CurrentSpeakerIndex returns the speaker index identifier.
@@ -267,13 +653,128 @@ func (t TTS) CurrentMaxRetries() int {
 	return t.maxRetries
 }
 
+// CurrentRetryPolicy returns the policy used to retry failed synthesis attempts.
+func (t TTS) CurrentRetryPolicy() RetryPolicy {
+	return t.retryPolicy
+}
+
+// CurrentHTTPClient returns the client requests to the tts-server are sent with, or nil for http.DefaultClient.
+func (t TTS) CurrentHTTPClient() *http.Client {
+	return t.httpClient
+}
+
+// CurrentLogger returns the logger synthesis logs are written to.
+func (t TTS) CurrentLogger() *slog.Logger {
+	return t.logger
+}
+
+// CurrentChunkPolicy returns the policy used to split long text into chunks.
+func (t TTS) CurrentChunkPolicy() ChunkPolicy {
+	return t.chunking
+}
+
+// CurrentTrimSilence returns how leading and trailing silence is trimmed from synthesized audio.
+// The zero value means no trimming.
+func (t TTS) CurrentTrimSilence() audio.TrimOptions {
+	return t.trim
+}
+
+// CurrentOutputSampleRate returns the sample rate synthesized audio is converted to.
+// 0 means the model's sample rate is kept.
+func (t TTS) CurrentOutputSampleRate() int {
+	return t.outputSampleRate
+}
+
+// CurrentOutputChannels returns the number of channels synthesized audio is converted to.
+// 0 means the model's channels are kept.
+func (t TTS) CurrentOutputChannels() int {
+	return t.outputChannels
+}
+
+// CurrentOutputFormat returns the format synthesized audio is encoded in.
+// Empty means the format is taken from the output file extension.
+func (t TTS) CurrentOutputFormat() OutputFormat {
+	return t.outputFormat
+}
+
+// CurrentEncoder returns the encoder used for format, or nil if the format isn't supported.
+func (t TTS) CurrentEncoder(format OutputFormat) Encoder {
+	enc, _ := t.encoder(format)
+	return enc
+}
+
+// CurrentLoudness returns the loudness synthesized audio is normalized to.
+// The zero value means no normalization.
+func (t TTS) CurrentLoudness() audio.LoudnessTarget {
+	return t.loudness
+}
+
+// CurrentVoiceConversion returns the voice conversion model and the target recordings synthesized speech is converted with.
+func (t TTS) CurrentVoiceConversion() (voiceconversion.Model, []string) {
+	return t.voiceConversion, t.vcTargets
+}
+
+// CurrentTemperature returns the temperature used for synthesis.
+// 0 means the model's default.
+func (t TTS) CurrentTemperature() float64 {
+	return t.temperature
+}
+
+// CurrentSpeed returns the speaking rate used for synthesis.
+// 0 means the model's default.
+func (t TTS) CurrentSpeed() float64 {
+	return t.speed
+}
+
+// CurrentVoices returns the library voices are looked up in.
+func (t TTS) CurrentVoices() *voices.Library {
+	return t.voices
+}
+
+// CurrentVoice returns the name of the voice profile last applied, or "" if none was.
+func (t TTS) CurrentVoice() string {
+	return t.voice
+}
+
+// CurrentSamplePolicy returns the policy ValidateSpeakerSample checks speaker samples against.
+func (t TTS) CurrentSamplePolicy() SamplePolicy {
+	return t.samplePolicy
+}
+
+// CurrentRunner returns the Runner used to execute Coqui TTS commands.
+func (t TTS) CurrentRunner() Runner {
+	return t.runner
+}
+
+// CurrentExecutable returns the path or name of the Coqui TTS executable.
+func (t TTS) CurrentExecutable() string {
+	return t.executable
+}
+
+// CurrentPython returns the Python interpreter used to run Coqui TTS.
+func (t TTS) CurrentPython() string {
+	return t.python
+}
+
+// CurrentWorkingDir returns the working directory of the Coqui TTS process.
+func (t TTS) CurrentWorkingDir() string {
+	return t.workingDir
+}
+
+// CurrentEnv returns the extra environment variables passed to the Coqui TTS process.
+func (t TTS) CurrentEnv() []string {
+	return t.env
+}
+
 // SetCurrentModel sets the TTS model to use for synthesis.
 func (t *TTS) SetCurrentIdentifier(m model.Identifier) error {
 	if err := m.Validate(); err != nil {
 		return fmt.Errorf("invalid TTS model specified: %s", err)
 	}
 	t.model = m
-	t.model.CurrentLanguage = m.DefaultLanguage
+	if m.CurrentLanguage == "" {
+		t.model.CurrentLanguage = m.DefaultLanguage
+	}
 	t.modelPath = ""
 	return nil
 }
@@ -306,10 +807,10 @@ func (t *TTS) SetCurrentVocoder(v vocoder.Model) error {
 // SetCurrentModelLanguage sets the target language for synthesis.
 func (t *TTS) SetCurrentModelLanguage(l model.Language) error {
 	if !l.IsSupported() {
-		return fmt.Errorf("invalid language specified: %s", l.String())
+		return fmt.Errorf("%w: invalid language specified: %s", ErrUnsupportedLanguage, l.String())
 	}
 	if !t.model.SupportsLanguage(l) {
-		return fmt.Errorf("model %s does not support language %s", t.model.Name(), l.String())
+		return fmt.Errorf("%w: model %s does not support language %s", ErrUnsupportedLanguage, t.model.Name(), l.String())
 	}
 	t.model.CurrentLanguage = l
 	return nil
@@ -318,10 +819,10 @@ func (t *TTS) SetCurrentModelLanguage(l model.Language) error {
 // SetCurrentVocoderLanguage sets the target language for synthesis.
 func (t *TTS) SetCurrentVocoderLanguage(l model.Language) error {
 	if !l.IsSupported() {
-		return fmt.Errorf("invalid language specified: %s", l.String())
+		return fmt.Errorf("%w: invalid language specified: %s", ErrUnsupportedLanguage, l.String())
 	}
 	if !t.vocoder.SupportsLanguage(l) {
-		return fmt.Errorf("vocoder %s does not support language %s", t.vocoder.Name(), l.String())
+		return fmt.Errorf("%w: vocoder %s does not support language %s", ErrUnsupportedLanguage, t.vocoder.Name(), l.String())
 	}
 	t.vocoder.CurrentLanguage = l
 	return nil
@@ -334,7 +835,7 @@ func (t *TTS) SetCurrentSpeaker(s string) error {
 	}
 	// speaker has an extension (e.g. ".wav", ".mp3").
 	if filepath.Ext(s) != "" && t.model.SupportsCloning() {
-		t.speakerSample = s
+		t.speakerSamples = []string{s}
 	} else {
 		t.speakerIdx = s
 	}
@@ -342,20 +843,39 @@ func (t *TTS) SetCurrentSpeaker(s string) error {
 }
 
 // SetCurrentSpeakerSample sets the path to the speaker sample file for voice cloning.
+// The file must exist and be a WAV file.
 func (t *TTS) SetCurrentSpeakerSample(samplePath string) error {
-	if samplePath == "" {
-		return fmt.Errorf("speaker sample path cannot be empty")
+	return t.SetCurrentSpeakerSamples(samplePath)
+}
+
+// SetCurrentSpeakerSamples sets several speaker sample files for voice cloning, replacing any set before.
+// Each file must exist and be a WAV file.
+func (t *TTS) SetCurrentSpeakerSamples(paths ...string) error {
+	if len(paths) == 0 {
+		return fmt.Errorf("at least one speaker sample is required")
+	}
+	for _, path := range paths {
+		if path == "" {
+			return fmt.Errorf("speaker sample path cannot be empty")
+		}
+		if _, err := audio.ReadFile(path); err != nil {
+			return fmt.Errorf("invalid speaker sample %s: %w", path, err)
+		}
 	}
 
-	t.speakerSample = samplePath
+	t.speakerSamples = slices.Clone(paths)
 	return nil
 }
 
 // SetCurrentSpeakerIndex sets the speaker index identifier for VITS models.
+// If the model's speakers have been listed with ListSpeakers, returns ErrUnknownSpeaker for an index that isn't one of them.
 func (t *TTS) SetCurrentSpeakerIndex(idx string) error {
 	if idx == "" {
 		return fmt.Errorf("speaker index cannot be empty")
 	}
+	if err := t.checkSpeaker(idx); err != nil {
+		return err
+	}
 
 	t.speakerIdx = idx
 	return nil
@@ -390,3 +910,336 @@ func (t *TTS) SetCurrentMaxRetries(r int) error {
 	t.maxRetries = r
 	return nil
 }
+
+// SetCurrentRetryPolicy sets the policy used to retry failed synthesis attempts.
+// If the policy sets MaxAttempts, it replaces the maximum number of retries.
+func (t *TTS) SetCurrentRetryPolicy(p RetryPolicy) error {
+	if err := p.Validate(); err != nil {
+		return fmt.Errorf("invalid retry policy: %w", err)
+	}
+
+	t.retryPolicy = p
+	if p.MaxAttempts > 0 {
+		t.maxRetries = p.MaxAttempts
+	}
+	return nil
+}
+
+// SetCurrentLogger sets the logger synthesis logs are written to.
+func (t *TTS) SetCurrentLogger(l *slog.Logger) error {
+	if l == nil {
+		return fmt.Errorf("logger cannot be nil")
+	}
+
+	t.logger = l
+	return nil
+}
+
+// SetCurrentTextLogging sets whether the text being synthesized is included in logs.
+func (t *TTS) SetCurrentTextLogging(enabled bool) error {
+	t.logText = enabled
+	return nil
+}
+
+// SetCurrentWorker switches synthesis to a persistent worker process that keeps models loaded between calls.
+// If command is empty, the bundled Python worker script is run with the configured Python interpreter.
+// Otherwise command is run instead; it must speak the same protocol as the bundled script.
+// Any previously configured backend is closed.
+func (t *TTS) SetCurrentWorker(command ...string) error {
+	if len(command) > 0 && command[0] == "" {
+		return fmt.Errorf("worker command cannot be empty")
+	}
+
+	if err := t.Close(); err != nil {
+		return err
+	}
+	t.backend = newWorker(command)
+	return nil
+}
+
+// SetCurrentHTTPClient sets the client requests to the tts-server are sent with.
+func (t *TTS) SetCurrentHTTPClient(c *http.Client) error {
+	if c == nil {
+		return errors.New("HTTP client cannot be nil")
+	}
+
+	t.httpClient = c
+	return nil
+}
+
+// SetCurrentServer switches synthesis to a running Coqui tts-server at baseURL.
+// Any previously configured backend is closed.
+func (t *TTS) SetCurrentServer(baseURL string) error {
+	server, err := newServerBackend(baseURL)
+	if err != nil {
+		return err
+	}
+
+	if err := t.Close(); err != nil {
+		return err
+	}
+	t.backend = server
+	return nil
+}
+
+// SetCurrentChunkPolicy sets the policy used to split long text into chunks.
+func (t *TTS) SetCurrentChunkPolicy(p ChunkPolicy) error {
+	if err := p.Validate(); err != nil {
+		return fmt.Errorf("invalid chunk policy: %w", err)
+	}
+
+	t.chunking = p
+	t.chunkingSet = true
+	return nil
+}
+
+// SetCurrentTrimSilence sets how leading and trailing silence is trimmed from synthesized audio.
+// Pass the zero value to turn trimming off.
+func (t *TTS) SetCurrentTrimSilence(opts audio.TrimOptions) error {
+	if opts != (audio.TrimOptions{}) {
+		if err := opts.Validate(); err != nil {
+			return fmt.Errorf("invalid trim options: %w", err)
+		}
+	}
+
+	t.trim = opts
+	return nil
+}
+
+// SetCurrentOutputSampleRate sets the sample rate synthesized audio is converted to.
+// Pass 0 to keep the model's sample rate.
+func (t *TTS) SetCurrentOutputSampleRate(rate int) error {
+	if rate < 0 {
+		return fmt.Errorf("invalid output sample rate: %d", rate)
+	}
+
+	t.outputSampleRate = rate
+	return nil
+}
+
+// SetCurrentOutputChannels sets the number of channels synthesized audio is converted to: 1 for mono or 2 for stereo.
+// Pass 0 to keep the model's channels.
+func (t *TTS) SetCurrentOutputChannels(channels int) error {
+	if channels < 0 || channels > 2 {
+		return fmt.Errorf("invalid output channels: %d, must be 1 (mono) or 2 (stereo)", channels)
+	}
+
+	t.outputChannels = channels
+	return nil
+}
+
+// SetCurrentOutputFormat sets the format synthesized audio is encoded in, whatever the output file extension.
+// Pass an empty format to go back to using the extension.
+// Returns an error matching ErrUnsupportedFormat if there is no encoder for format.
+func (t *TTS) SetCurrentOutputFormat(format OutputFormat) error {
+	format = OutputFormat(strings.ToLower(strings.TrimPrefix(string(format), ".")))
+	if format != "" {
+		if _, err := t.encoder(format); err != nil {
+			return err
+		}
+	}
+
+	t.outputFormat = format
+	return nil
+}
+
+// SetCurrentEncoder registers the encoder used for format, replacing any built-in encoder.
+func (t *TTS) SetCurrentEncoder(format OutputFormat, enc Encoder) error {
+	if enc == nil {
+		return errors.New("encoder cannot be nil")
+	}
+
+	encoders := make(map[OutputFormat]Encoder, len(t.encoders)+1)
+	for f, e := range t.encoders {
+		encoders[f] = e
+	}
+	encoders[OutputFormat(strings.ToLower(strings.TrimPrefix(string(format), ".")))] = enc
+	t.encoders = encoders
+	return nil
+}
+
+// SetCurrentLoudness sets the loudness synthesized audio is normalized to.
+// Pass the zero value to turn normalization off.
+func (t *TTS) SetCurrentLoudness(target audio.LoudnessTarget) error {
+	if target != (audio.LoudnessTarget{}) {
+		if err := target.Validate(); err != nil {
+			return fmt.Errorf("invalid loudness target: %w", err)
+		}
+	}
+
+	t.loudness = target
+	return nil
+}
+
+// SetCurrentVoiceConversion converts all synthesized speech with vc into the voice of the target recordings.
+// Without targets, only the model used by Convert is set.
+func (t *TTS) SetCurrentVoiceConversion(vc voiceconversion.Model, targetWavs ...string) error {
+	if vc.Category != model.TypeVoiceConversion {
+		return fmt.Errorf("invalid voice conversion model specified: %s is not a voice conversion model", vc.Name())
+	}
+	if err := vc.Validate(); err != nil {
+		return fmt.Errorf("invalid voice conversion model specified: %s", err)
+	}
+	if err := checkWavs(targetWavs); err != nil {
+		return err
+	}
+
+	t.voiceConversion = vc
+	t.vcTargets = slices.Clone(targetWavs)
+	return nil
+}
+
+// SetCurrentTemperature sets how varied the synthesized voice sounds, e.g. 0.75 for XTTS.
+// Use 0 for the model's default.
+func (t *TTS) SetCurrentTemperature(temperature float64) error {
+	if temperature < 0 {
+		return fmt.Errorf("temperature cannot be negative")
+	}
+
+	t.temperature = temperature
+	return nil
+}
+
+// SetCurrentSpeed sets the speaking rate, where 1 is normal speed.
+// Use 0 for the model's default.
+func (t *TTS) SetCurrentSpeed(speed float64) error {
+	if speed < 0 {
+		return fmt.Errorf("speed cannot be negative")
+	}
+
+	t.speed = speed
+	return nil
+}
+
+// SetCurrentVoices sets the library voices are looked up in.
+func (t *TTS) SetCurrentVoices(lib *voices.Library) error {
+	if lib == nil {
+		return fmt.Errorf("voice library cannot be nil")
+	}
+
+	t.voices = lib
+	return nil
+}
+
+// SetCurrentVoice applies the named voice profile from the voice library.
+// The profile's model and language are used if it sets them, and its speaker samples, speaker index,
+// temperature and speed replace the current ones. Nothing is changed if the profile can't be applied.
+// A profile with a temperature or speed needs the worker, so set WithWorker before WithVoice.
+func (t *TTS) SetCurrentVoice(name string) error {
+	if t.voices == nil {
+		return fmt.Errorf("no voice library set, use WithVoices")
+	}
+	profile, err := t.voices.Get(name)
+	if err != nil {
+		return err
+	}
+	if (profile.Temperature != 0 || profile.Speed != 0) && !t.supportsStyle() {
+		return fmt.Errorf("voice %s: %w: temperature and speed need the worker, use WithWorker before WithVoice", name, ErrNotSupported)
+	}
+
+	next := *t
+	if profile.Model != "" {
+		id, err := model.ParseIdentifier(profile.Model)
+		if err != nil {
+			return fmt.Errorf("voice %s: %w", name, err)
+		}
+		if err := next.SetCurrentIdentifier(id); err != nil {
+			return fmt.Errorf("voice %s: %w", name, err)
+		}
+	}
+	if profile.Language != "" {
+		if err := next.SetCurrentModelLanguage(profile.Language); err != nil {
+			return fmt.Errorf("voice %s: %w", name, err)
+		}
+	}
+
+	next.speakerSamples = nil
+	if len(profile.Samples) > 0 {
+		if err := next.SetCurrentSpeakerSamples(profile.Samples...); err != nil {
+			return fmt.Errorf("voice %s: %w", name, err)
+		}
+	}
+	next.speakerIdx = ""
+	if profile.SpeakerIndex != "" {
+		if err := next.SetCurrentSpeakerIndex(profile.SpeakerIndex); err != nil {
+			return fmt.Errorf("voice %s: %w", name, err)
+		}
+	}
+	next.temperature = profile.Temperature
+	next.speed = profile.Speed
+	next.voice = name
+
+	*t = next
+	return nil
+}
+
+// SetCurrentSamplePolicy sets the thresholds ValidateSpeakerSample checks speaker samples against.
+func (t *TTS) SetCurrentSamplePolicy(p SamplePolicy) error {
+	if err := p.Validate(); err != nil {
+		return fmt.Errorf("invalid sample policy: %w", err)
+	}
+
+	t.samplePolicy = p
+	return nil
+}
+
+// SetCurrentRunner sets the Runner used to execute Coqui TTS commands.
+func (t *TTS) SetCurrentRunner(r Runner) error {
+	if r == nil {
+		return fmt.Errorf("runner cannot be nil")
+	}
+
+	t.runner = r
+	return nil
+}
+
+// SetCurrentExecutable sets the path or name of the Coqui TTS executable.
+func (t *TTS) SetCurrentExecutable(path string) error {
+	if path == "" {
+		return fmt.Errorf("executable cannot be empty")
+	}
+
+	t.executable = path
+	return nil
+}
+
+// SetCurrentPython sets the Python interpreter used to run "python -m TTS.bin.synthesize".
+func (t *TTS) SetCurrentPython(interpreter string) error {
+	if interpreter == "" {
+		return fmt.Errorf("python interpreter cannot be empty")
+	}
+
+	t.python = interpreter
+	return nil
+}
+
+// SetCurrentWorkingDir sets the working directory of the Coqui TTS process.
+func (t *TTS) SetCurrentWorkingDir(dir string) error {
+	if dir == "" {
+		return fmt.Errorf("working directory cannot be empty")
+	}
+
+	info, err := os.Stat(dir)
+	if err != nil {
+		return fmt.Errorf("working directory does not exist: %s", dir)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("working directory is not a directory: %s", dir)
+	}
+
+	t.workingDir = dir
+	return nil
+}
+
+// SetCurrentEnv adds extra environment variables for the Coqui TTS process.
+// Each variable must be in "KEY=value" form.
+func (t *TTS) SetCurrentEnv(vars ...string) error {
+	for _, v := range vars {
+		if key, _, ok := strings.Cut(v, "="); !ok || key == "" {
+			return fmt.Errorf("invalid environment variable %q: expected KEY=value", v)
+		}
+	}
+
+	t.env = append(t.env, vars...)
+	return nil
+}
@@ -1,16 +1,22 @@
 package coqui
 
 import (
+	"errors"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/pixellini/go-coqui/model"
 	"github.com/pixellini/go-coqui/models/tts"
 	"github.com/pixellini/go-coqui/models/vocoder"
+	"github.com/pixellini/go-coqui/voices"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNew_Default(t *testing.T) {
-	coqui, err := New()
+	coqui, err := New(WithRunner(&fakeRunner{}))
 	assert.NoError(t, err, "New should not return an error for valid input")
 
 	assert.Equal(t, tts.PresetXTTSv2, coqui.CurrentModel(), "CurrentModel should return the default model")
@@ -24,28 +30,28 @@ func TestNew_Default(t *testing.T) {
 }
 
 func TestNewWithModelXttsV2(t *testing.T) {
-	coqui, err := NewWithModelXttsV2()
+	coqui, err := NewWithModelXttsV2(WithRunner(&fakeRunner{}))
 	assert.NoError(t, err, "NewWithModel should not return an error for valid input")
 	assert.NotNil(t, coqui, "TTS instance should not be nil")
 	assert.Equal(t, tts.PresetXTTSv2, coqui.CurrentModel(), "Current model should be XTTSv2")
 }
 
 func TestNewWithModelXttsV1(t *testing.T) {
-	coqui, err := NewWithModelXttsV1()
+	coqui, err := NewWithModelXttsV1(WithRunner(&fakeRunner{}))
 	assert.NoError(t, err, "NewWithModel should not return an error for valid input")
 	assert.NotNil(t, coqui, "TTS instance should not be nil")
 	assert.Equal(t, tts.PresetXTTSv1, coqui.CurrentModel(), "Current model should be XTTSv1")
 }
 
 func TestNewWithModelYourTTS(t *testing.T) {
-	coqui, err := NewWithModelYourTTS()
+	coqui, err := NewWithModelYourTTS(WithRunner(&fakeRunner{}))
 	assert.NoError(t, err, "NewWithModel should not return an error for valid input")
 	assert.NotNil(t, coqui, "TTS instance should not be nil")
 	assert.Equal(t, tts.PresetYourTTS, coqui.CurrentModel(), "Current model should be YourTTS")
 }
 
 func TestNewWithModelBark(t *testing.T) {
-	coqui, err := NewWithModelBark()
+	coqui, err := NewWithModelBark(WithRunner(&fakeRunner{}))
 	assert.NoError(t, err, "NewWithModel should not return an error for valid input")
 	assert.NotNil(t, coqui, "TTS instance should not be nil")
 	assert.Equal(t, tts.PresetBark, coqui.CurrentModel(), "Current model should be Bark")
@@ -62,7 +68,7 @@ func TestTTSName(t *testing.T) {
 }
 
 func TestTTSName_Multilingual(t *testing.T) {
-	coqui, err := New()
+	coqui, err := New(WithRunner(&fakeRunner{}))
 	assert.NoError(t, err, "New should not return an error for valid input")
 
 	expectedName := "tts_models/multilingual/multi-dataset/xtts_v2"
@@ -79,7 +85,7 @@ func TestTTSVocoderName(t *testing.T) {
 }
 
 func TestTTSSetters(t *testing.T) {
-	coqui, err := New()
+	coqui, err := New(WithRunner(&fakeRunner{}))
 	assert.NoError(t, err, "New should not return an error for valid input")
 
 	newModel := tts.PresetBark
@@ -95,8 +101,9 @@ func TestTTSSetters(t *testing.T) {
 	coqui.SetCurrentModelLanguage(model.Portuguese)
 	assert.Equal(t, model.Portuguese, coqui.CurrentModelLanguage(), "SetCurrentModelLanguage should update the current model language")
 
-	coqui.SetCurrentSpeakerSample("sample.wav")
-	assert.Equal(t, "sample.wav", coqui.CurrentSpeakerSample(), "SetCurrentSpeakerSample should update the current speaker sample")
+	sample := writeTargets(t, "sample.wav")[0]
+	coqui.SetCurrentSpeakerSample(sample)
+	assert.Equal(t, sample, coqui.CurrentSpeakerSample(), "SetCurrentSpeakerSample should update the current speaker sample")
 
 	coqui.SetCurrentSpeakerIndex("speaker1")
 	assert.Equal(t, "speaker1", coqui.CurrentSpeakerIndex(), "SetCurrentSpeakerIndex should update the current speaker index")
@@ -110,3 +117,136 @@ func TestTTSSetters(t *testing.T) {
 	coqui.SetCurrentMaxRetries(5)
 	assert.Equal(t, 5, coqui.CurrentMaxRetries(), "SetCurrentMaxRetries should update the current max retries")
 }
+
+func TestSetCurrentSpeakerSamples(t *testing.T) {
+	samples := writeTargets(t, "one.wav", "two.wav")
+	notWav := filepath.Join(t.TempDir(), "notes.txt")
+	require.NoError(t, os.WriteFile(notWav, []byte("not audio"), 0644))
+
+	tests := []struct {
+		name    string
+		paths   []string
+		wantErr bool
+	}{
+		{name: "valid samples", paths: samples},
+		{name: "no samples", wantErr: true},
+		{name: "empty path", paths: []string{samples[0], ""}, wantErr: true},
+		{name: "missing file", paths: []string{samples[0], "missing.wav"}, wantErr: true},
+		{name: "not a wav", paths: []string{notWav}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			coqui, err := New(WithRunner(&fakeRunner{}))
+			require.NoError(t, err)
+
+			err = coqui.SetCurrentSpeakerSamples(tt.paths...)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Empty(t, coqui.CurrentSpeakerSamples(), "a failed call should leave the samples unchanged")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.paths, coqui.CurrentSpeakerSamples())
+			assert.Equal(t, tt.paths[0], coqui.CurrentSpeakerSample())
+		})
+	}
+}
+
+func TestSetCurrentVoice(t *testing.T) {
+	samples := writeTargets(t, "anna-1.wav", "anna-2.wav")
+	lib, err := voices.New(
+		voices.Profile{
+			Name:        "narrator-anna",
+			Samples:     samples,
+			Model:       "tts_models/multilingual/multi-dataset/xtts_v2",
+			Language:    model.German,
+			Temperature: 0.6,
+			Speed:       1.1,
+		},
+		voices.Profile{Name: "vctk-p225", Model: "tts_models/en/vctk/vits", SpeakerIndex: "p225"},
+		voices.Profile{Name: "broken", Samples: []string{"missing.wav"}},
+	)
+	require.NoError(t, err)
+
+	// The worker isn't started until the first synthesis, so no Python is needed.
+	coqui, err := New(WithRunner(&fakeRunner{}), WithWorkerCommand("fake-worker"), WithVoices(lib), WithVoice("narrator-anna"))
+	require.NoError(t, err)
+	assert.Equal(t, tts.PresetXTTSv2.Name(), coqui.Name())
+	assert.Equal(t, model.German, coqui.CurrentModelLanguage())
+	assert.Equal(t, samples, coqui.CurrentSpeakerSamples())
+	assert.Equal(t, 0.6, coqui.CurrentTemperature())
+	assert.Equal(t, 1.1, coqui.CurrentSpeed())
+	assert.Equal(t, "narrator-anna", coqui.CurrentVoice())
+
+	require.NoError(t, coqui.SetCurrentVoice("vctk-p225"))
+	assert.Equal(t, "tts_models/en/vctk/vits", coqui.Name())
+	assert.Equal(t, "p225", coqui.CurrentSpeakerIndex())
+	assert.Empty(t, coqui.CurrentSpeakerSamples(), "a voice should replace the speaker samples of the last one")
+	assert.Zero(t, coqui.CurrentTemperature())
+	assert.Zero(t, coqui.CurrentSpeed())
+
+	assert.Error(t, coqui.SetCurrentVoice("broken"))
+	assert.Equal(t, "vctk-p225", coqui.CurrentVoice(), "a voice that can't be applied should change nothing")
+	assert.Equal(t, "p225", coqui.CurrentSpeakerIndex())
+
+	assert.ErrorIs(t, coqui.SetCurrentVoice("nobody"), voices.ErrVoiceNotFound)
+
+	_, err = New(WithRunner(&fakeRunner{}), WithVoice("narrator-anna"))
+	assert.Error(t, err, "WithVoice needs a voice library")
+}
+
+func TestSetCurrentVoice_StyleNeedsWorker(t *testing.T) {
+	lib, err := voices.New(voices.Profile{Name: "narrator-anna", Language: model.German, Speed: 1.1})
+	require.NoError(t, err)
+
+	coqui, runner := newTestTTS(t, WithVoices(lib))
+	assert.ErrorIs(t, coqui.SetCurrentVoice("narrator-anna"), ErrNotSupported, "the tts command can't apply a speed")
+	assert.Empty(t, coqui.CurrentVoice())
+	assert.Zero(t, coqui.CurrentSpeed())
+	assert.Equal(t, model.English, coqui.CurrentModelLanguage(), "a voice that can't be applied should change nothing")
+
+	respondWithWav(runner, toneWav(t, 24000, 0.5, 100*time.Millisecond))
+	_, err = coqui.Synthesize("Hello world", "hello.wav")
+	assert.NoError(t, err, "synthesis should still work after the voice was rejected")
+}
+
+func TestNew_ExecutableNotFound(t *testing.T) {
+	_, err := New(WithExecutable(filepath.Join(t.TempDir(), "missing-tts")))
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrExecutableNotFound, "New should fail when the executable doesn't exist")
+
+	var notFound *ExecutableNotFoundError
+	require.True(t, errors.As(err, &notFound), "The error should be an ExecutableNotFoundError")
+	assert.Contains(t, notFound.Name, "missing-tts")
+}
+
+func TestNew_ExecutableFound(t *testing.T) {
+	executable := filepath.Join(t.TempDir(), "tts")
+	require.NoError(t, os.WriteFile(executable, []byte("#!/bin/sh\n"), 0755))
+
+	coqui, err := New(WithExecutable(executable))
+	require.NoError(t, err)
+	assert.Equal(t, executable, coqui.CurrentExecutable())
+}
+
+func TestTTSCommand(t *testing.T) {
+	coqui := &TTS{
+		executable: "/opt/venv/bin/tts",
+		workingDir: "/srv/app",
+		env:        []string{"HF_HOME=/cache"},
+	}
+
+	cmd := coqui.command([]string{argText, "hi"})
+	assert.Equal(t, Command{
+		Name: "/opt/venv/bin/tts",
+		Args: []string{argText, "hi"},
+		Dir:  "/srv/app",
+		Env:  []string{"HF_HOME=/cache"},
+	}, cmd)
+
+	coqui.python = "/opt/venv/bin/python"
+	cmd = coqui.command([]string{argText, "hi"})
+	assert.Equal(t, "/opt/venv/bin/python", cmd.Name, "The interpreter should replace the executable")
+	assert.Equal(t, []string{"-m", pythonModule, argText, "hi"}, cmd.Args)
+}
@@ -0,0 +1,175 @@
+package coqui
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/pixellini/go-coqui/audio"
+)
+
+// OutputFormat is the encoding synthesized audio is delivered in.
+// Formats are named after the file extension they are stored with.
+type OutputFormat string
+
+const (
+	// FormatWAV is the WAV audio Coqui produces.
+	FormatWAV OutputFormat = "wav"
+	// FormatFLAC is lossless FLAC, encoded in Go.
+	FormatFLAC OutputFormat = "flac"
+	// FormatPCM is raw little-endian PCM without a header, also used for ".raw" files.
+	FormatPCM OutputFormat = "pcm"
+	// FormatMP3 is MP3, encoded by ffmpeg.
+	FormatMP3 OutputFormat = "mp3"
+	// FormatOpus is Opus in an Ogg container, encoded by ffmpeg.
+	FormatOpus OutputFormat = "opus"
+	// FormatMuLaw is raw 8kHz mono G.711 μ-law, as played by Asterisk and other IVR systems.
+	FormatMuLaw OutputFormat = "ulaw"
+	// FormatALaw is raw 8kHz mono G.711 A-law.
+	FormatALaw OutputFormat = "alaw"
+	// FormatMuLawWAV is 8kHz mono G.711 μ-law in a WAV file (format tag 7).
+	// WAV files are written as FormatWAV by default, so it has to be selected explicitly.
+	FormatMuLawWAV OutputFormat = "ulaw-wav"
+	// FormatALawWAV is 8kHz mono G.711 A-law in a WAV file (format tag 6).
+	FormatALawWAV OutputFormat = "alaw-wav"
+)
+
+// telephonySampleRate is the sample rate of G.711 audio.
+const telephonySampleRate = 8000
+
+// formatAliases maps file extensions to formats that aren't named after them.
+var formatAliases = map[string]OutputFormat{
+	"raw":   FormatPCM,
+	"mulaw": FormatMuLaw,
+}
+
+// g711Formats maps the G.711 output formats to the companding they use.
+var g711Formats = map[OutputFormat]audio.Format{
+	FormatMuLaw:    audio.MuLaw,
+	FormatALaw:     audio.ALaw,
+	FormatMuLawWAV: audio.MuLaw,
+	FormatALawWAV:  audio.ALaw,
+}
+
+// formatForPath returns the format implied by the extension of path.
+// Paths without an extension are written as WAV.
+func formatForPath(path string) OutputFormat {
+	ext := strings.ToLower(strings.TrimPrefix(filepath.Ext(path), "."))
+	if ext == "" {
+		return FormatWAV
+	}
+	if format, ok := formatAliases[ext]; ok {
+		return format
+	}
+	return OutputFormat(ext)
+}
+
+// Encoder converts decoded audio to an output format.
+type Encoder interface {
+	// Encode writes clip to w in the encoder's format.
+	Encode(ctx context.Context, clip *audio.Clip, w io.Writer) error
+}
+
+// EncoderFunc adapts an ordinary function to an Encoder.
+type EncoderFunc func(ctx context.Context, clip *audio.Clip, w io.Writer) error
+
+// Encode calls f.
+func (f EncoderFunc) Encode(ctx context.Context, clip *audio.Clip, w io.Writer) error {
+	return f(ctx, clip, w)
+}
+
+// CommandEncoder encodes audio by piping it through an external program such as ffmpeg or opusenc.
+// The program is given the audio as WAV on stdin and must write the encoded audio to stdout.
+type CommandEncoder struct {
+	// Name is the program to run.
+	Name string
+	// Args are the arguments passed to the program.
+	Args []string
+	// Runner runs the program. If nil, ExecRunner is used.
+	Runner Runner
+}
+
+// Encode runs the program with the clip as WAV on stdin and copies its stdout to w.
+func (e *CommandEncoder) Encode(ctx context.Context, clip *audio.Clip, w io.Writer) error {
+	wav, err := clip.Bytes()
+	if err != nil {
+		return err
+	}
+
+	runner := e.Runner
+	if runner == nil {
+		runner = ExecRunner{}
+	}
+	out, err := runner.Run(ctx, Command{Name: e.Name, Args: e.Args, Stdin: wav})
+	if err != nil {
+		return fmt.Errorf("%s failed: %w", e.Name, newCommandError(out, err))
+	}
+	if len(out.Stdout) == 0 {
+		return fmt.Errorf("%s produced no output", e.Name)
+	}
+
+	_, err = w.Write(out.Stdout)
+	return err
+}
+
+// ffmpegArgs returns the arguments that make ffmpeg encode WAV from stdin with codecArgs and write container to stdout.
+func ffmpegArgs(container string, codecArgs ...string) []string {
+	args := []string{"-hide_banner", "-loglevel", "error", "-f", "wav", "-i", "pipe:0"}
+	args = append(args, codecArgs...)
+	return append(args, "-f", container, "pipe:1")
+}
+
+// encoder returns the encoder for format: one registered with WithEncoder, or a built-in one.
+// External encoders run through the TTS instance's Runner.
+func (t TTS) encoder(format OutputFormat) (Encoder, error) {
+	if enc, ok := t.encoders[format]; ok {
+		return enc, nil
+	}
+
+	switch format {
+	case FormatWAV, FormatMuLawWAV, FormatALawWAV:
+		return EncoderFunc(func(_ context.Context, clip *audio.Clip, w io.Writer) error {
+			return clip.Encode(w)
+		}), nil
+	case FormatFLAC:
+		return EncoderFunc(func(_ context.Context, clip *audio.Clip, w io.Writer) error {
+			return clip.EncodeFLAC(w)
+		}), nil
+	case FormatPCM, FormatMuLaw, FormatALaw:
+		return EncoderFunc(func(_ context.Context, clip *audio.Clip, w io.Writer) error {
+			return clip.EncodePCM(w)
+		}), nil
+	case FormatMP3:
+		return &CommandEncoder{
+			Name:   "ffmpeg",
+			Args:   ffmpegArgs("mp3", "-c:a", "libmp3lame", "-q:a", "2"),
+			Runner: t.runner,
+		}, nil
+	case FormatOpus:
+		// Opus only runs at 8, 12, 16, 24 or 48kHz, so resample models like VITS at 22.05kHz.
+		return &CommandEncoder{
+			Name:   "ffmpeg",
+			Args:   ffmpegArgs("ogg", "-c:a", "libopus", "-b:a", "32k", "-ar", "48000"),
+			Runner: t.runner,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("%w: %q", ErrUnsupportedFormat, format)
+}
+
+// encode encodes clip in format.
+func (t TTS) encode(ctx context.Context, clip *audio.Clip, format OutputFormat) ([]byte, error) {
+	enc, err := t.encoder(format)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := enc.Encode(ctx, clip, &buf); err != nil {
+		return nil, fmt.Errorf("failed to encode audio as %s: %w", format, err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,214 @@
+package coqui
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/pixellini/go-coqui/audio"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatForPath(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected OutputFormat
+	}{
+		{path: "hello.wav", expected: FormatWAV},
+		{path: "hello.FLAC", expected: FormatFLAC},
+		{path: "hello.raw", expected: FormatPCM},
+		{path: "dir.v2/hello", expected: FormatWAV},
+		{path: "hello.opus", expected: FormatOpus},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			assert.Equal(t, tt.expected, formatForPath(tt.path))
+		})
+	}
+}
+
+func TestSynthesize_FLAC(t *testing.T) {
+	coqui, runner := newTestTTS(t)
+	respondWithWav(runner, toneWav(t, 24000, 0.5, 500*time.Millisecond))
+
+	result, err := coqui.Synthesize("Hello world", "hello.flac")
+	require.NoError(t, err)
+	assert.Equal(t, FormatFLAC, result.Format)
+	assert.Equal(t, 500*time.Millisecond, result.Duration)
+	assert.Equal(t, 24000, result.SampleRate)
+
+	data, err := os.ReadFile(result.OutputPath)
+	require.NoError(t, err)
+	assert.Equal(t, "fLaC", string(data[:4]))
+
+	entries, err := os.ReadDir(coqui.CurrentOutputDir())
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "The intermediate WAV file should be removed")
+}
+
+func TestSynthesize_PCM(t *testing.T) {
+	coqui, runner := newTestTTS(t, WithOutputFormat(FormatPCM))
+	wav := toneWav(t, 24000, 0.5, 100*time.Millisecond)
+	respondWithWav(runner, wav)
+
+	result, err := coqui.Synthesize("Hello world", "hello.wav")
+	require.NoError(t, err)
+	assert.Equal(t, FormatPCM, result.Format, "WithOutputFormat should win over the extension")
+
+	data, err := os.ReadFile(result.OutputPath)
+	require.NoError(t, err)
+	assert.Equal(t, wav[44:], data)
+
+	raw, err := coqui.SynthesizeBytes(context.Background(), "Hello world")
+	require.NoError(t, err)
+	assert.Equal(t, wav[44:], raw)
+}
+
+func TestSynthesize_MP3(t *testing.T) {
+	coqui, runner := newTestTTS(t)
+	wav := toneWav(t, 24000, 0.5, 100*time.Millisecond)
+	runner.respond = func(cmd Command) (Output, error) {
+		if cmd.Name == "ffmpeg" {
+			return Output{Stdout: []byte("ID3 fake mp3")}, nil
+		}
+		return Output{}, os.WriteFile(argValue(cmd.Args, argOutPath), wav, 0644)
+	}
+
+	result, err := coqui.Synthesize("Hello world", "hello.mp3")
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(result.OutputPath)
+	require.NoError(t, err)
+	assert.Equal(t, "ID3 fake mp3", string(data))
+	assert.Equal(t, 100*time.Millisecond, result.Duration)
+
+	calls := runner.calls()
+	require.Len(t, calls, 2)
+	assert.Equal(t, wav, calls[1].Stdin, "ffmpeg should be given the synthesized WAV on stdin")
+	assert.Contains(t, calls[1].Args, "libmp3lame")
+}
+
+func TestSynthesize_UnsupportedFormat(t *testing.T) {
+	coqui, runner := newTestTTS(t)
+
+	_, err := coqui.Synthesize("Hello world", "hello.xyz")
+	assert.ErrorIs(t, err, ErrUnsupportedFormat)
+	assert.Empty(t, runner.calls(), "Unsupported formats should fail before synthesizing")
+}
+
+func TestWithEncoder(t *testing.T) {
+	enc := EncoderFunc(func(_ context.Context, clip *audio.Clip, w io.Writer) error {
+		_, err := w.Write([]byte(clip.Duration().String()))
+		return err
+	})
+	coqui, runner := newTestTTS(t, WithEncoder("opus", enc))
+	respondWithWav(runner, toneWav(t, 24000, 0.5, 100*time.Millisecond))
+
+	result, err := coqui.Synthesize("Hello world", "hello.opus")
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(result.OutputPath)
+	require.NoError(t, err)
+	assert.Equal(t, "100ms", string(data))
+	assert.Len(t, runner.calls(), 1, "The registered encoder should replace ffmpeg")
+
+	_, err = New(WithEncoder("opus", nil))
+	assert.Error(t, err)
+}
+
+func TestCommandEncoder_Failure(t *testing.T) {
+	clip := audio.Silence(8000, 1, audio.PCM16, 10*time.Millisecond)
+
+	enc := &CommandEncoder{Name: "opusenc", Runner: &fakeRunner{respond: func(Command) (Output, error) {
+		return Output{Stderr: []byte("bad input")}, errors.New("exit status 1")
+	}}}
+	err := enc.Encode(context.Background(), clip, io.Discard)
+	var cmdErr *CommandError
+	require.ErrorAs(t, err, &cmdErr)
+	assert.Contains(t, err.Error(), "opusenc failed")
+
+	enc.Runner = &fakeRunner{respond: func(Command) (Output, error) { return Output{}, nil }}
+	assert.Error(t, enc.Encode(context.Background(), clip, io.Discard), "Empty output should be an error")
+}
+
+func TestSynthesize_G711(t *testing.T) {
+	tests := []struct {
+		name       string
+		outputPath string
+		format     OutputFormat
+		law        audio.Format
+		wav        bool
+	}{
+		{name: "Raw μ-law from the extension", outputPath: "prompt.ulaw", law: audio.MuLaw},
+		{name: "Raw A-law", outputPath: "prompt.raw", format: FormatALaw, law: audio.ALaw},
+		{name: "WAV-wrapped μ-law", outputPath: "prompt.wav", format: FormatMuLawWAV, law: audio.MuLaw, wav: true},
+		{name: "WAV-wrapped A-law", outputPath: "prompt.wav", format: FormatALawWAV, law: audio.ALaw, wav: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			coqui, runner := newTestTTS(t, WithOutputSampleRate(16000))
+			respondWithWav(runner, toneWav(t, 22050, 0.5, 500*time.Millisecond))
+
+			var result *SynthesisResult
+			var err error
+			if tt.format == "" {
+				result, err = coqui.Synthesize("Press one for sales", tt.outputPath)
+			} else {
+				result, err = coqui.SynthesizeAs(context.Background(), "Press one for sales", tt.outputPath, tt.format)
+			}
+			require.NoError(t, err)
+			assert.Equal(t, 8000, result.SampleRate, "G.711 should always be 8kHz")
+			assert.Equal(t, 1, result.Channels)
+			assert.InDelta(t, 500*time.Millisecond, result.Duration, float64(time.Millisecond))
+
+			data, err := os.ReadFile(result.OutputPath)
+			require.NoError(t, err)
+			if !tt.wav {
+				assert.Len(t, data, 4000, "Raw G.711 should be one byte per sample")
+				return
+			}
+			clip, err := audio.DecodeBytes(data)
+			require.NoError(t, err)
+			assert.Equal(t, tt.law, clip.Format)
+			assert.Equal(t, 8000, clip.SampleRate)
+		})
+	}
+}
+
+func TestSetCurrentOutputFormat_Unsupported(t *testing.T) {
+	coqui, runner := newTestTTS(t, WithOutputFormat(FormatFLAC))
+
+	assert.ErrorIs(t, coqui.SetCurrentOutputFormat("aiff"), ErrUnsupportedFormat)
+	assert.Equal(t, FormatFLAC, coqui.CurrentOutputFormat(), "An unsupported format should not be set")
+	assert.ErrorIs(t, WithOutputFormat("aiff").apply(coqui), ErrUnsupportedFormat)
+
+	_, err := coqui.SynthesizeAs(context.Background(), "Hello world", "hello.aiff", "aiff")
+	assert.ErrorIs(t, err, ErrUnsupportedFormat)
+	_, err = coqui.SynthesizeBytesAs(context.Background(), "Hello world", "aiff")
+	assert.ErrorIs(t, err, ErrUnsupportedFormat)
+	assert.Empty(t, runner.calls(), "Unsupported formats should fail before synthesizing")
+
+	require.NoError(t, coqui.SetCurrentEncoder("aiff", EncoderFunc(func(context.Context, *audio.Clip, io.Writer) error { return nil })))
+	assert.NoError(t, coqui.SetCurrentOutputFormat("aiff"), "A format with a registered encoder should be accepted")
+	assert.NoError(t, coqui.SetCurrentOutputFormat(""), "An empty format goes back to using the extension")
+}
+
+func TestSynthesizeBytesAs(t *testing.T) {
+	coqui, runner := newTestTTS(t)
+	respondWithWav(runner, toneWav(t, 24000, 0.5, 100*time.Millisecond))
+
+	data, err := coqui.SynthesizeBytesAs(context.Background(), "Hello world", FormatMuLaw)
+	require.NoError(t, err)
+	assert.Len(t, data, 800)
+	assert.Empty(t, coqui.CurrentOutputFormat(), "The format should only apply to the call")
+
+	data, err = coqui.SynthesizeBytes(context.Background(), "Hello world")
+	require.NoError(t, err)
+	assert.Equal(t, "RIFF", string(data[:4]))
+}
@@ -0,0 +1,277 @@
+package coqui
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"os/exec"
+)
+
+// Sentinel errors for the failures go-coqui can recognise.
+// Use errors.Is to check for them; CommandError matches the sentinel its output was classified as.
+var (
+	// ErrOutputExists is returned when the output file already exists.
+	ErrOutputExists = errors.New("output file already exists")
+	// ErrExecutableNotFound is returned when the Coqui TTS executable or Python interpreter cannot be found.
+	ErrExecutableNotFound = errors.New("coqui executable not found")
+	// ErrModelNotFound is returned when Coqui doesn't know the requested model.
+	ErrModelNotFound = errors.New("model not found")
+	// ErrModelDownloadFailed is returned when Coqui fails to download the model files.
+	ErrModelDownloadFailed = errors.New("model download failed")
+	// ErrUnsupportedLanguage is returned when the language isn't supported by the model.
+	ErrUnsupportedLanguage = errors.New("unsupported language")
+	// ErrSpeakerRequired is returned when a multi-speaker model is used without a speaker index or sample.
+	ErrSpeakerRequired = errors.New("speaker required")
+	// ErrCUDAUnavailable is returned when CUDA was requested but isn't usable.
+	ErrCUDAUnavailable = errors.New("CUDA unavailable")
+	// ErrOutOfMemory is returned when the device runs out of memory during synthesis.
+	ErrOutOfMemory = errors.New("out of memory")
+	// ErrServerUnavailable is returned when the tts-server can't be reached or is temporarily unable to respond.
+	ErrServerUnavailable = errors.New("tts-server unavailable")
+	// ErrNotSupported is returned when the configured backend doesn't support a requested feature.
+	ErrNotSupported = errors.New("not supported")
+	// ErrUnsupportedFormat is returned when audio is requested in an output format without an encoder.
+	ErrUnsupportedFormat = errors.New("unsupported output format")
+	// ErrUnknownSpeaker is returned when the speaker index isn't one of the speakers listed for the model.
+	ErrUnknownSpeaker = errors.New("unknown speaker")
+	// ErrSpeakerSampleRejected is returned when a speaker sample fails the thresholds of the sample policy.
+	ErrSpeakerSampleRejected = errors.New("speaker sample rejected")
+)
+
+// permanentErrors are failures that will happen again if the same command is retried.
+var permanentErrors = []error{
+	ErrOutputExists,
+	ErrExecutableNotFound,
+	ErrModelNotFound,
+	ErrUnsupportedLanguage,
+	ErrSpeakerRequired,
+	ErrCUDAUnavailable,
+	ErrNotSupported,
+	ErrUnsupportedFormat,
+	ErrUnknownSpeaker,
+	ErrSpeakerSampleRejected,
+}
+
+// transientErrors are failures that may succeed if the same command is retried.
+var transientErrors = []error{
+	ErrModelDownloadFailed,
+	ErrOutOfMemory,
+	ErrServerUnavailable,
+}
+
+// IsPermanent reports whether err is a failure that retrying won't fix,
+// such as a bad model name or a missing executable.
+func IsPermanent(err error) bool {
+	return isAny(err, permanentErrors)
+}
+
+// IsTransient reports whether err is a failure that may succeed on retry,
+// such as a failed model download or running out of GPU memory.
+func IsTransient(err error) bool {
+	return isAny(err, transientErrors)
+}
+
+// isAny reports whether err matches any of the targets.
+func isAny(err error, targets []error) bool {
+	for _, target := range targets {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// ExecutableNotFoundError describes an executable that could not be resolved.
+// It matches ErrExecutableNotFound with errors.Is.
+type ExecutableNotFoundError struct {
+	// Name is the executable that was looked up.
+	Name string
+	// Err is the underlying lookup error.
+	Err error
+}
+
+// Error returns a description of the missing executable.
+func (e *ExecutableNotFoundError) Error() string {
+	return fmt.Sprintf("%s: %q: %v", ErrExecutableNotFound, e.Name, e.Err)
+}
+
+// Unwrap returns the underlying lookup error.
+func (e *ExecutableNotFoundError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is ErrExecutableNotFound.
+func (e *ExecutableNotFoundError) Is(target error) bool {
+	return target == ErrExecutableNotFound
+}
+
+// CommandError is returned when the Coqui TTS command fails.
+// It unwraps to both the classified sentinel error (if any) and the underlying execution error,
+// so errors.Is(err, ErrModelNotFound) and errors.As(err, &exitErr) both work.
+type CommandError struct {
+	// Kind is the sentinel error the output was classified as.
+	// Nil when the failure couldn't be classified.
+	Kind error
+	// ExitCode is the exit code of the process, or -1 if it didn't exit normally.
+	ExitCode int
+	// Output is what the command wrote to stdout and stderr.
+	Output []byte
+	// Err is the underlying execution error.
+	Err error
+}
+
+// newCommandError classifies a failed command from its output.
+func newCommandError(out Output, err error) *CommandError {
+	combined := out.Combined()
+
+	exitCode := -1
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		exitCode = exitErr.ExitCode()
+	}
+
+	kind := classifyOutput(combined)
+	if errors.Is(err, exec.ErrNotFound) {
+		kind = ErrExecutableNotFound
+	}
+
+	return &CommandError{
+		Kind:     kind,
+		ExitCode: exitCode,
+		Output:   combined,
+		Err:      err,
+	}
+}
+
+// Error returns a description of the failure.
+func (e *CommandError) Error() string {
+	if e.Kind != nil {
+		return fmt.Sprintf("TTS command failed: %s: %v", e.Kind, e.Err)
+	}
+	return fmt.Sprintf("TTS command failed: %v", e.Err)
+}
+
+// Unwrap returns the classified sentinel error and the underlying execution error.
+func (e *CommandError) Unwrap() []error {
+	if e.Kind == nil {
+		return []error{e.Err}
+	}
+	return []error{e.Kind, e.Err}
+}
+
+// ServerError is returned when the tts-server responds with an error status.
+// Like CommandError, it matches the sentinel its response body was classified as,
+// and matches ErrServerUnavailable for statuses that are worth retrying.
+type ServerError struct {
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int
+	// Kind is the sentinel error the response body was classified as.
+	// Nil when the failure couldn't be classified.
+	Kind error
+	// Body is the response body, which usually holds the server's error message.
+	Body []byte
+}
+
+// newServerError classifies a failed tts-server response.
+func newServerError(statusCode int, body []byte) *ServerError {
+	kind := classifyOutput(body)
+	if kind == nil {
+		switch statusCode {
+		case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			kind = ErrServerUnavailable
+		}
+	}
+
+	return &ServerError{
+		StatusCode: statusCode,
+		Kind:       kind,
+		Body:       body,
+	}
+}
+
+// Error returns a description of the failure.
+func (e *ServerError) Error() string {
+	if e.Kind != nil {
+		return fmt.Sprintf("tts-server request failed with status %d: %s", e.StatusCode, e.Kind)
+	}
+	return fmt.Sprintf("tts-server request failed with status %d", e.StatusCode)
+}
+
+// Unwrap returns the classified sentinel error.
+func (e *ServerError) Unwrap() error {
+	return e.Kind
+}
+
+// outputPatterns maps known Coqui TTS (and PyTorch) error messages to sentinel errors.
+// Patterns are matched against the lowercased output in order, so more specific patterns come first.
+// e.g. "CUDA out of memory" must be classified as ErrOutOfMemory rather than ErrCUDAUnavailable.
+var outputPatterns = []struct {
+	pattern string
+	err     error
+}{
+	{"out of memory", ErrOutOfMemory},
+	{"outofmemoryerror", ErrOutOfMemory},
+
+	{"cuda is not available", ErrCUDAUnavailable},
+	{"torch not compiled with cuda enabled", ErrCUDAUnavailable},
+	{"no cuda gpus are available", ErrCUDAUnavailable},
+	{"found no nvidia driver", ErrCUDAUnavailable},
+	{"cuda driver version is insufficient", ErrCUDAUnavailable},
+
+	{"not found in the model list", ErrModelNotFound},
+	{"model not found", ErrModelNotFound},
+	{"is not a valid model", ErrModelNotFound},
+
+	{"failed to download", ErrModelDownloadFailed},
+	{"connectionerror", ErrModelDownloadFailed},
+	{"max retries exceeded with url", ErrModelDownloadFailed},
+	{"temporary failure in name resolution", ErrModelDownloadFailed},
+	{"read timed out", ErrModelDownloadFailed},
+	{"remote end closed connection", ErrModelDownloadFailed},
+
+	{"is not supported. supported languages are", ErrUnsupportedLanguage},
+	{"language is not supported", ErrUnsupportedLanguage},
+	{"unsupported language", ErrUnsupportedLanguage},
+
+	{"you need to define either a `speaker", ErrSpeakerRequired},
+	{"multi-speaker model", ErrSpeakerRequired},
+}
+
+// Echoed text markers in tts command output. The command prints the input text after " > Text:",
+// and the sentences it was split into on the line after " > Text splitted to sentences.".
+var (
+	echoedTextPrefix      = []byte(" > Text:")
+	echoedSentencesMarker = []byte(" > Text splitted to sentences.")
+)
+
+// withoutEchoedText returns output without the lines that echo the input text,
+// so text that happens to contain an error message isn't mistaken for one.
+func withoutEchoedText(output []byte) []byte {
+	var kept [][]byte
+	skipNext := false
+	for _, line := range bytes.Split(output, []byte("\n")) {
+		switch {
+		case skipNext:
+			skipNext = false
+		case bytes.HasPrefix(line, echoedSentencesMarker):
+			skipNext = true
+		case bytes.HasPrefix(line, echoedTextPrefix):
+		default:
+			kept = append(kept, line)
+		}
+	}
+	return bytes.Join(kept, []byte("\n"))
+}
+
+// classifyOutput returns the sentinel error matching the command output, or nil if it isn't recognised.
+// Lines echoing the input text are ignored.
+func classifyOutput(output []byte) error {
+	lower := bytes.ToLower(withoutEchoedText(output))
+	for _, p := range outputPatterns {
+		if bytes.Contains(lower, []byte(p.pattern)) {
+			return p.err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package coqui
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/pixellini/go-coqui/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClassifyOutput(t *testing.T) {
+	tests := []struct {
+		name     string
+		output   string
+		expected error
+	}{
+		{"CUDA out of memory", "torch.cuda.OutOfMemoryError: CUDA out of memory. Tried to allocate 2.00 GiB", ErrOutOfMemory},
+		{"CUDA not compiled", "AssertionError: Torch not compiled with CUDA enabled", ErrCUDAUnavailable},
+		{"Unknown model", "ValueError: Model `tts_models/en/foo/bar` not found in the model list.", ErrModelNotFound},
+		{"Download failure", "requests.exceptions.ConnectionError: HTTPSConnectionPool(host='huggingface.co', port=443): Max retries exceeded with url", ErrModelDownloadFailed},
+		{"XTTS language", "AssertionError: ❗ Language xx is not supported. Supported languages are ['en', 'es']", ErrUnsupportedLanguage},
+		{"Speaker required", "ValueError:  [!] Looks like you are using a multi-speaker model. You need to define either a `speaker_idx` or a `speaker_wav` to use a multi-speaker model.", ErrSpeakerRequired},
+		{"Unrecognised", "Traceback: something else went wrong", nil},
+		{"Echoed text", " > Text: The server ran out of memory.\nTraceback: something else went wrong", nil},
+		{"Echoed sentences", " > Text splitted to sentences.\n['The model not found in the model list.']\nTraceback: something else went wrong", nil},
+		{"Error after echoed text", " > Text: Hello\nRuntimeError: CUDA out of memory.", ErrOutOfMemory},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, classifyOutput([]byte(tt.output)))
+		})
+	}
+}
+
+func TestCommandError(t *testing.T) {
+	execErr := errors.New("exit status 1")
+	err := newCommandError(Output{Stderr: []byte("Torch not compiled with CUDA enabled")}, execErr)
+
+	assert.ErrorIs(t, err, ErrCUDAUnavailable, "CommandError should match its classified sentinel")
+	assert.ErrorIs(t, err, execErr, "CommandError should match the underlying error")
+	assert.Equal(t, -1, err.ExitCode)
+	assert.True(t, IsPermanent(err))
+	assert.False(t, IsTransient(err))
+
+	unknown := newCommandError(Output{Stdout: []byte("???")}, execErr)
+	assert.Nil(t, unknown.Kind)
+	assert.False(t, IsPermanent(unknown))
+	assert.False(t, IsTransient(unknown))
+}
+
+func TestCommandError_ExitCode(t *testing.T) {
+	if _, err := os.Stat("/bin/sh"); err != nil {
+		t.Skip("requires /bin/sh")
+	}
+
+	out, runErr := ExecRunner{}.Run(context.Background(), Command{Name: "/bin/sh", Args: []string{"-c", "echo 'CUDA out of memory' 1>&2; exit 3"}})
+	err := newCommandError(out, runErr)
+	assert.Equal(t, 3, err.ExitCode)
+	assert.True(t, IsTransient(err))
+
+	var exitErr *exec.ExitError
+	assert.True(t, errors.As(err, &exitErr), "The underlying *exec.ExitError should be reachable with errors.As")
+}
+
+func TestSynthesize_ClassifiedError(t *testing.T) {
+	coqui, runner := newTestTTS(t, WithMaxRetries(1))
+	runner.respond = func(cmd Command) (Output, error) {
+		return Output{Stderr: []byte("Model `tts_models/en/foo/bar` not found in the model list.")}, errors.New("exit status 1")
+	}
+
+	_, err := coqui.Synthesize("Hello world", "hello.wav")
+	assert.ErrorIs(t, err, ErrModelNotFound)
+
+	var cmdErr *CommandError
+	require.True(t, errors.As(err, &cmdErr))
+	assert.Contains(t, string(cmdErr.Output), "not found in the model list")
+}
+
+func TestSynthesize_EchoedTextNotClassified(t *testing.T) {
+	coqui, runner := newTestTTS(t, WithMaxRetries(1))
+	runner.respond = func(cmd Command) (Output, error) {
+		stdout := " > Text: " + argValue(cmd.Args, argText) + "\n"
+		return Output{Stdout: []byte(stdout), Stderr: []byte("Traceback: something else went wrong")}, errors.New("exit status 1")
+	}
+
+	_, err := coqui.Synthesize("The GPU ran out of memory.", "echo.wav")
+	assert.NotErrorIs(t, err, ErrOutOfMemory)
+	assert.False(t, IsTransient(err))
+}
+
+func TestSynthesize_OutputExists(t *testing.T) {
+	coqui, _ := newTestTTS(t)
+	require.NoError(t, os.WriteFile(coqui.CurrentOutputDir()+"exists.wav", nil, 0644))
+
+	_, err := coqui.Synthesize("Hello world", "exists.wav")
+	assert.ErrorIs(t, err, ErrOutputExists)
+	assert.True(t, IsPermanent(err))
+}
+
+func TestSetCurrentModelLanguage_Unsupported(t *testing.T) {
+	coqui := &TTS{model: model.Identifier{SupportedLanguages: []model.Language{model.English}}}
+	err := coqui.SetCurrentModelLanguage(model.French)
+	assert.ErrorIs(t, err, ErrUnsupportedLanguage)
+}
@@ -0,0 +1,44 @@
+package coqui
+
+import (
+	"context"
+	"log/slog"
+)
+
+// discardHandler is a slog.Handler that drops every record.
+// It keeps the library silent when no logger is configured.
+type discardHandler struct{}
+
+func (discardHandler) Enabled(context.Context, slog.Level) bool  { return false }
+func (discardHandler) Handle(context.Context, slog.Record) error { return nil }
+func (d discardHandler) WithAttrs([]slog.Attr) slog.Handler      { return d }
+func (d discardHandler) WithGroup(string) slog.Handler           { return d }
+
+// discardLogger is used when no logger has been set.
+var discardLogger = slog.New(discardHandler{})
+
+// log returns the configured logger, or a logger that discards everything.
+func (t TTS) log() *slog.Logger {
+	if t.logger == nil {
+		return discardLogger
+	}
+	return t.logger
+}
+
+// textAttr describes the text being synthesized.
+// The text itself is only logged when text logging is enabled, otherwise only its length is.
+func (t TTS) textAttr(text string) slog.Attr {
+	if t.logText {
+		return slog.String("text", text)
+	}
+	return slog.Int("text_length", len(text))
+}
+
+// outputAttr holds the command output, which Coqui prefixes with the text being synthesized.
+// Like the text, it is redacted unless text logging is enabled.
+func (t TTS) outputAttr(output []byte) slog.Attr {
+	if t.logText {
+		return slog.String("output", string(output))
+	}
+	return slog.Int("output_length", len(output))
+}
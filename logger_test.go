@@ -0,0 +1,63 @@
+package coqui
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newBufferLogger(buf *bytes.Buffer) *slog.Logger {
+	return slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+}
+
+func TestLogger_RedactsTextByDefault(t *testing.T) {
+	var buf bytes.Buffer
+	coqui, _ := newTestTTS(t, WithLogger(newBufferLogger(&buf)))
+
+	_, err := coqui.Synthesize("my secret text", "hello.wav")
+	require.NoError(t, err)
+
+	logs := buf.String()
+	assert.NotContains(t, logs, "my secret text", "Text should be redacted by default")
+	assert.Contains(t, logs, `"text_length":14`)
+	assert.Contains(t, logs, `"msg":"synthesis complete"`)
+	assert.Contains(t, logs, `"model":"`+coqui.Name()+`"`)
+	assert.Contains(t, logs, `"attempts":1`)
+	assert.Contains(t, logs, `"output_path":`)
+	assert.Contains(t, logs, `"duration":`)
+}
+
+func TestLogger_TextLogging(t *testing.T) {
+	var buf bytes.Buffer
+	coqui, _ := newTestTTS(t, WithLogger(newBufferLogger(&buf)), WithTextLogging(true))
+
+	_, err := coqui.Synthesize("hello there", "hello.wav")
+	require.NoError(t, err)
+	assert.Contains(t, buf.String(), `"text":"hello there"`)
+}
+
+func TestLogger_FailedAttempts(t *testing.T) {
+	var buf bytes.Buffer
+	coqui, runner := newTestTTS(t, WithLogger(newBufferLogger(&buf)), WithMaxRetries(2))
+	runner.respond = func(cmd Command) (Output, error) {
+		return Output{Stdout: []byte(" > Text: my secret text")}, errors.New("exit status 1")
+	}
+
+	_, err := coqui.Synthesize("my secret text", "hello.wav")
+	require.Error(t, err)
+
+	logs := buf.String()
+	assert.Contains(t, logs, `"msg":"synthesis attempt failed"`)
+	assert.Contains(t, logs, `"attempt":2`)
+	assert.NotContains(t, logs, "my secret text", "Command output echoes the text and should be redacted")
+}
+
+func TestLogger_SilentByDefault(t *testing.T) {
+	coqui := TTS{}
+	assert.False(t, coqui.log().Enabled(context.Background(), slog.LevelError), "The library should be silent when no logger is set")
+}
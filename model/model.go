@@ -53,7 +53,8 @@ type Identifier struct {
 }
 
 // NewModel creates a new custom Model Identifier.
-// This is useful for models that are not predefined in the Coqui TTS library.
+// This is useful for models that are not predefined in the Coqui TTS library, so the dataset can be any name.
+// Use ParseIdentifier to build one from a model name string.
 func NewModel(t Type, m BaseModel, d Dataset, l Language) (Identifier, error) {
 	if t == "" {
 		return Identifier{}, fmt.Errorf("model type cannot be empty")
@@ -74,18 +75,12 @@ func NewModel(t Type, m BaseModel, d Dataset, l Language) (Identifier, error) {
 	if !l.IsSupported() {
 		return Identifier{}, fmt.Errorf("unsupported language: %s", l)
 	}
-	if !d.isPreset() {
-		return Identifier{}, fmt.Errorf("unsupported dataset: %s", d)
-	}
-
 	var supportedLanguages = []Language{l}
 	if l == Universal || l == Multilingual {
 		// If the language is Universal or Multilingual, we assume it supports all languages.
 		supportedLanguages = GetSupportedLanguages()
 	}
 
-	// TODO: Need a method to point to a custom model via a path.
-	// IDEA: It would be cool to add a method that splits the Model Name from a string like "tts_models/en/ek1/tacotron2" into its components.
 	return Identifier{
 		Category:             t,
 		Dataset:              d,
@@ -98,22 +93,30 @@ func NewModel(t Type, m BaseModel, d Dataset, l Language) (Identifier, error) {
 	}, nil
 }
 
-// Name returns a string representation of the model identifier.
-// It formats the model identifier as "category/dataset/language/model".
+// Name returns a string representation of the model identifier in its current language.
+// It formats the model identifier as "category/language/dataset/model", the way Coqui TTS names models.
 func (id Identifier) Name() string {
-	return fmt.Sprintf("%s/%s/%s/%s", id.GetType(), id.GetCurrentLanguage(), id.GetDataset(), id.GetBaseModel())
+	return id.nameFor(id.GetCurrentLanguage())
 }
 
 // NameList returns a list of string representations of the model identifier for each supported language.
+// Models trained on every language have a single name, e.g. "tts_models/multilingual/multi-dataset/xtts_v2".
 func (id Identifier) NameList() []string {
 	var names []string
 	for _, lang := range id.SupportedLanguages {
-		name := fmt.Sprintf("%s/%s/%s/%s", id.GetType(), id.GetDataset(), lang, id.GetBaseModel())
-		names = append(names, name)
+		name := id.nameFor(lang)
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
 	}
 	return names
 }
 
+// nameFor returns the name of the model when it's used in lang.
+func (id Identifier) nameFor(lang Language) string {
+	return fmt.Sprintf("%s/%s/%s/%s", id.GetType(), id.nameLanguage(lang), id.GetDataset(), id.GetBaseModel())
+}
+
 // IsValid checks if the model identifier is valid.
 func (id Identifier) IsValid() bool {
 	return id.Validate() == nil
@@ -8,6 +8,7 @@ import (
 )
 
 var MockBaseModel = BaseModel("mock-base-model")
+var MockBaseModel2 = BaseModel("mock-base-model2")
 var MockDataset = Dataset("mock-dataset")
 var MockDataset2 = Dataset("mock-dataset2")
 
@@ -39,24 +40,50 @@ func TestIdentifier_Name(t *testing.T) {
 	m := Identifier{
 		Category:        TypeTTS,
 		CurrentLanguage: English,
-		Dataset:         MockDataset,
-		Model:           MockBaseModel,
+		Dataset:         DatasetLJSpeech,
+		Model:           "tacotron2-DDC",
 	}
 	assert.Equal(t, "tts_models/en/ljspeech/tacotron2-DDC", m.Name())
+
+	multilingual := Identifier{
+		Category:           TypeTTS,
+		CurrentLanguage:    French,
+		Dataset:            DatasetMultiDataset,
+		Model:              "xtts_v2",
+		SupportedLanguages: GetSupportedLanguages(),
+	}
+	assert.Equal(t, "tts_models/multilingual/multi-dataset/xtts_v2", multilingual.Name())
+
+	universal := Identifier{
+		Category:           TypeVocoder,
+		CurrentLanguage:    English,
+		Dataset:            DatasetLibriTTS,
+		Model:              "wavegrad",
+		SupportedLanguages: GetSupportedLanguages(),
+	}
+	assert.Equal(t, "vocoder_models/universal/libri-tts/wavegrad", universal.Name())
 }
 
 func TestIdentifier_NameList(t *testing.T) {
 	m := Identifier{
 		Category:           TypeTTS,
-		Dataset:            MockDataset,
-		Model:              MockBaseModel,
+		Dataset:            DatasetLJSpeech,
+		Model:              "tacotron2-DDC",
 		SupportedLanguages: []Language{English, French},
 	}
 	expected := []string{
-		"tts_models/ljspeech/en/tacotron2-DDC",
-		"tts_models/ljspeech/fr/tacotron2-DDC",
+		"tts_models/en/ljspeech/tacotron2-DDC",
+		"tts_models/fr/ljspeech/tacotron2-DDC",
+	}
+	assert.Equal(t, expected, m.NameList(), "NameList should use the same ordering as Name")
+
+	multilingual := Identifier{
+		Category:           TypeTTS,
+		Dataset:            DatasetMultiDataset,
+		Model:              "xtts_v2",
+		SupportedLanguages: GetSupportedLanguages(),
 	}
-	assert.Equal(t, expected, m.NameList())
+	assert.Equal(t, []string{"tts_models/multilingual/multi-dataset/xtts_v2"}, multilingual.NameList())
 }
 
 func TestIdentifier_IsValid_Validate(t *testing.T) {
@@ -134,7 +161,7 @@ func TestModelList_FilterMethods(t *testing.T) {
 	m3 := Identifier{
 		Category:           TypeTTS,
 		Dataset:            MockDataset2,
-		Model:              MockBaseModel,
+		Model:              MockBaseModel2,
 		DefaultLanguage:    German,
 		SupportedLanguages: []Language{German},
 	}
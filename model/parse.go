@@ -0,0 +1,91 @@
+package model
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+)
+
+var (
+	registryMu sync.RWMutex
+	// registry holds the predefined models registered by the tts, vocoder and voiceconversion packages, keyed by name.
+	registry = map[string]Identifier{}
+)
+
+// RegisterPresets makes predefined models known to ParseIdentifier.
+// The tts, vocoder and voiceconversion packages register their presets when they are imported.
+func RegisterPresets(models ...Identifier) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	for _, m := range models {
+		for _, name := range m.NameList() {
+			registry[name] = m
+		}
+	}
+}
+
+// ParseIdentifier parses a Coqui model name such as "tts_models/en/ek1/tacotron2" or
+// "tts_models/multilingual/multi-dataset/xtts_v2" into an Identifier.
+// Names of predefined models return the preset, with its full metadata and the named language selected.
+// Any other well-formed name returns a custom identifier.
+// Names with the dataset before the language, as NameList used to produce them, are also accepted.
+func ParseIdentifier(name string) (Identifier, error) {
+	parts := strings.Split(strings.Trim(strings.TrimSpace(name), "/"), "/")
+	if len(parts) != 4 {
+		return Identifier{}, fmt.Errorf("invalid model name %q: expected type/language/dataset/model", name)
+	}
+	for _, part := range parts {
+		if part == "" {
+			return Identifier{}, fmt.Errorf("invalid model name %q: empty segment", name)
+		}
+	}
+
+	category, lang, dataset, base := Type(parts[0]), Language(parts[1]), Dataset(parts[2]), BaseModel(parts[3])
+	if !slices.Contains(types, category) {
+		return Identifier{}, fmt.Errorf("invalid model name %q: unsupported model type: %s", name, category)
+	}
+	if !lang.IsSupported() && Language(dataset).IsSupported() {
+		lang, dataset = Language(dataset), Dataset(lang)
+	}
+	if !lang.IsSupported() {
+		return Identifier{}, fmt.Errorf("invalid model name %q: unsupported language: %s", name, lang)
+	}
+
+	// Models trained on every language are registered once, so a name in one of their languages falls back to that.
+	registryMu.RLock()
+	var preset Identifier
+	var ok bool
+	for _, l := range []Language{lang, Multilingual, Universal} {
+		if preset, ok = registry[fmt.Sprintf("%s/%s/%s/%s", category, l, dataset, base)]; ok {
+			break
+		}
+	}
+	registryMu.RUnlock()
+	if ok && preset.SupportsLanguage(lang) {
+		preset.SupportedLanguages = slices.Clone(preset.SupportedLanguages)
+		if preset.SupportsLanguage(lang) && !lang.isMultilingual() {
+			preset.CurrentLanguage = lang
+		}
+		return preset, nil
+	}
+
+	return NewModel(category, base, dataset, lang)
+}
+
+// isMultilingual reports whether the language stands for every language rather than a single one.
+func (l Language) isMultilingual() bool {
+	return l == Universal || l == Multilingual
+}
+
+// nameLanguage returns the language segment of the model's name when it's used in lang.
+// Models trained on every language are published under a single name: "universal" for vocoders, "multilingual" otherwise.
+func (id Identifier) nameLanguage(lang Language) Language {
+	if !id.SupportsLanguage(Multilingual) {
+		return lang
+	}
+	if id.Category == TypeVocoder {
+		return Universal
+	}
+	return Multilingual
+}
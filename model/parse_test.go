@@ -0,0 +1,89 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseIdentifier(t *testing.T) {
+	css10 := Identifier{
+		Category:           TypeTTS,
+		Dataset:            DatasetCSS10,
+		Model:              "vits",
+		DefaultLanguage:    Spanish,
+		CurrentLanguage:    Spanish,
+		SupportedLanguages: []Language{Spanish, French, German},
+	}
+	xtts := Identifier{
+		Category:             TypeTTS,
+		Dataset:              DatasetMultiDataset,
+		Model:                "xtts_v2",
+		DefaultLanguage:      English,
+		CurrentLanguage:      English,
+		SupportedLanguages:   GetSupportedLanguages(),
+		SupportsVoiceCloning: true,
+	}
+	RegisterPresets(css10, xtts)
+
+	tests := []struct {
+		name     string
+		input    string
+		preset   *Identifier
+		language Language
+	}{
+		{name: "Preset in another supported language", input: "tts_models/fr/css10/vits", preset: &css10, language: French},
+		{name: "Multilingual preset", input: "tts_models/multilingual/multi-dataset/xtts_v2", preset: &xtts, language: English},
+		{name: "Multilingual preset named by a language", input: "tts_models/de/multi-dataset/xtts_v2", preset: &xtts, language: German},
+		{name: "Dataset before language", input: "tts_models/css10/de/vits", preset: &css10, language: German},
+		{name: "Surrounding whitespace and slashes", input: " /tts_models/es/css10/vits/ ", preset: &css10, language: Spanish},
+		{name: "Custom model", input: "tts_models/en/my-dataset/my-model", language: English},
+		{name: "Preset architecture in an unsupported language", input: "tts_models/it/css10/vits", language: Italian},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, err := ParseIdentifier(tt.input)
+			require.NoError(t, err)
+			assert.Equal(t, tt.language, id.CurrentLanguage)
+
+			if tt.preset == nil {
+				assert.True(t, id.IsCustom, "Unknown models should be custom")
+				return
+			}
+			assert.False(t, id.IsCustom)
+			assert.Equal(t, tt.preset.SupportedLanguages, id.SupportedLanguages)
+			assert.Equal(t, tt.preset.SupportsVoiceCloning, id.SupportsVoiceCloning)
+			assert.Equal(t, tt.preset.DefaultLanguage, id.DefaultLanguage)
+		})
+	}
+}
+
+func TestParseIdentifier_RoundTrip(t *testing.T) {
+	id, err := ParseIdentifier("vocoder_models/universal/libri-tts/my-vocoder")
+	require.NoError(t, err)
+	assert.Equal(t, "vocoder_models/universal/libri-tts/my-vocoder", id.Name())
+
+	id, err = ParseIdentifier("voice_conversion_models/multilingual/vctk/my-vc")
+	require.NoError(t, err)
+	assert.Equal(t, "voice_conversion_models/multilingual/vctk/my-vc", id.Name())
+}
+
+func TestParseIdentifier_Invalid(t *testing.T) {
+	tests := []string{
+		"",
+		"tts_models/en/ljspeech",
+		"tts_models/en/ljspeech/tacotron2/extra",
+		"tts_models/en//tacotron2",
+		"music_models/en/ljspeech/tacotron2",
+		"tts_models/xx/ljspeech/tacotron2",
+	}
+
+	for _, input := range tests {
+		t.Run(input, func(t *testing.T) {
+			_, err := ParseIdentifier(input)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package coqui
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/pixellini/go-coqui/model"
+)
+
+var (
+	// modelInfoLine matches a field in the output of "tts --model_info_by_name", e.g. " > license : CPML".
+	modelInfoLine = regexp.MustCompile(`^\s*>\s*([^:]+?)\s*:\s*(.*?)\s*$`)
+	// modelInfoURL matches the first URL in a field, which may hold a Python list of URLs.
+	modelInfoURL = regexp.MustCompile(`https?://[^\s'",\]]+`)
+)
+
+// ModelInfo describes a model as reported by Coqui TTS.
+// Fields Coqui TTS doesn't report for the model are left empty.
+type ModelInfo struct {
+	// Name is the model name, e.g. "tts_models/multilingual/multi-dataset/xtts_v2".
+	Name string
+	// Description is a short description of the model.
+	Description string
+	// License is the license the model is released under, e.g. "CPML" for XTTS or "apache 2.0".
+	License string
+	// DefaultVocoder is the name of the vocoder used when none is set.
+	// Empty for models that don't need a separate vocoder.
+	DefaultVocoder string
+	// Commit is the commit of the model release.
+	Commit string
+	// URL is where the model files are downloaded from.
+	URL string
+	// Author is the author of the model.
+	Author string
+	// Contact is the contact address for the model.
+	Contact string
+	// TOSRequired reports whether the model's terms of service have to be agreed to before it's downloaded.
+	TOSRequired bool
+	// Fields holds every field Coqui TTS reported, including those without a dedicated field above.
+	Fields map[string]string
+}
+
+// ModelInfo looks up the description, license, default vocoder and other details of a model
+// with "tts --model_info_by_name".
+// Returns ErrModelNotFound if Coqui TTS doesn't know the model.
+func (t TTS) ModelInfo(ctx context.Context, id model.Identifier) (*ModelInfo, error) {
+	return t.modelInfo(ctx, argModelInfoByName, id.Name())
+}
+
+// ModelInfoByIndex looks up a model by its position in the "tts --list_models" output for its type,
+// with "tts --model_info_by_idx", e.g. ModelInfoByIndex(ctx, model.TypeTTS, 1) for the first TTS model.
+// Returns ErrModelNotFound if there is no model at that index.
+func (t TTS) ModelInfoByIndex(ctx context.Context, modelType model.Type, idx int) (*ModelInfo, error) {
+	if idx < 1 {
+		return nil, fmt.Errorf("model index must be at least 1, got %d", idx)
+	}
+	return t.modelInfo(ctx, argModelInfoByIdx, fmt.Sprintf("%s/%d", modelType, idx))
+}
+
+// modelInfo runs a model info query and parses its output.
+func (t TTS) modelInfo(ctx context.Context, flag, query string) (*ModelInfo, error) {
+	if err := t.requireCommand("model info"); err != nil {
+		return nil, err
+	}
+	out, err := t.runCommand(ctx, []string{flag, query})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get model info for %s: %w", query, newCommandError(out, err))
+	}
+
+	info := parseModelInfo(string(out.Stdout))
+	if len(info.Fields) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrModelNotFound, query)
+	}
+	if info.Name == "" && flag == argModelInfoByName {
+		info.Name = query
+	}
+	return info, nil
+}
+
+// parseModelInfo parses the " > key : value" lines Coqui TTS prints for a model.
+func parseModelInfo(output string) *ModelInfo {
+	info := &ModelInfo{Fields: map[string]string{}}
+	var modelType, language, dataset, name string
+
+	for _, line := range strings.Split(output, "\n") {
+		m := modelInfoLine.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		key := strings.ReplaceAll(strings.ToLower(m[1]), " ", "_")
+		value := m[2]
+		info.Fields[key] = value
+
+		switch key {
+		case "model_type":
+			modelType = value
+		case "language_supported", "language":
+			language = value
+		case "dataset_used", "dataset":
+			dataset = value
+		case "model_name":
+			name = value
+		case "description":
+			info.Description = value
+		case "license":
+			info.License = value
+		case "default_vocoder":
+			if !isNone(value) {
+				info.DefaultVocoder = value
+			}
+		case "commit":
+			if !isNone(value) {
+				info.Commit = value
+			}
+		case "model_url", "github_rls_url", "hf_url":
+			if info.URL == "" {
+				info.URL = modelInfoURL.FindString(value)
+			}
+		case "author":
+			info.Author = value
+		case "contact":
+			info.Contact = value
+		case "tos_required":
+			info.TOSRequired = strings.EqualFold(value, "true")
+		}
+	}
+
+	if modelType != "" && language != "" && dataset != "" && name != "" {
+		info.Name = fmt.Sprintf("%s/%s/%s/%s", modelType, language, dataset, name)
+	}
+	return info
+}
+
+// isNone reports whether a value is Python's None or empty.
+func isNone(value string) bool {
+	return value == "" || value == "None" || value == "null"
+}
@@ -0,0 +1,138 @@
+package coqui
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pixellini/go-coqui/model"
+	"github.com/pixellini/go-coqui/models/tts"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// xttsInfoOutput is the output of "tts --model_info_by_name tts_models/multilingual/multi-dataset/xtts_v2".
+const xttsInfoOutput = ` > model type : tts_models
+ > language supported : multilingual
+ > dataset used : multi-dataset
+ > model_name : xtts_v2
+ > description : XTTS-v2 by Coqui with 17 languages.
+ > hf_url : ['https://coqui.gateway.scarf.sh/hf-coqui/XTTS-v2/main/model.pth', 'https://coqui.gateway.scarf.sh/hf-coqui/XTTS-v2/main/config.json']
+ > model_hash : 10f92b55c512af7a8d39d650547a15a7
+ > default_vocoder : None
+ > commit : 480a6cdf7
+ > license : CPML
+ > contact : info@coqui.ai
+ > tos_required : True
+`
+
+// tacotronInfoOutput is the output of "tts --model_info_by_idx tts_models/3" from an older Coqui TTS.
+const tacotronInfoOutput = ` > model type : tts_models
+ > language supported : en
+ > dataset used : ljspeech
+ > model_name : tacotron2-DDC
+ > description : Tacotron2 with Double Decoder Consistency.
+ > default_vocoder : vocoder_models/en/ljspeech/hifigan_v2
+ > commit : bae2ad0f
+ > author : Eren Gölge @erogol
+ > license : apache 2.0
+ > github_rls_url : https://coqui.gateway.scarf.sh/v0.6.1_models/tts_models--en--ljspeech--tacotron2-DDC.zip
+`
+
+func TestParseModelInfo(t *testing.T) {
+	tests := []struct {
+		name     string
+		output   string
+		expected ModelInfo
+	}{
+		{
+			name:   "XTTS",
+			output: xttsInfoOutput,
+			expected: ModelInfo{
+				Name:        "tts_models/multilingual/multi-dataset/xtts_v2",
+				Description: "XTTS-v2 by Coqui with 17 languages.",
+				License:     "CPML",
+				Commit:      "480a6cdf7",
+				URL:         "https://coqui.gateway.scarf.sh/hf-coqui/XTTS-v2/main/model.pth",
+				Contact:     "info@coqui.ai",
+				TOSRequired: true,
+			},
+		},
+		{
+			name:   "Tacotron2",
+			output: tacotronInfoOutput,
+			expected: ModelInfo{
+				Name:           "tts_models/en/ljspeech/tacotron2-DDC",
+				Description:    "Tacotron2 with Double Decoder Consistency.",
+				License:        "apache 2.0",
+				DefaultVocoder: "vocoder_models/en/ljspeech/hifigan_v2",
+				Commit:         "bae2ad0f",
+				URL:            "https://coqui.gateway.scarf.sh/v0.6.1_models/tts_models--en--ljspeech--tacotron2-DDC.zip",
+				Author:         "Eren Gölge @erogol",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info := parseModelInfo(tt.output)
+			fields := info.Fields
+			info.Fields = nil
+			assert.Equal(t, tt.expected, *info)
+			assert.NotEmpty(t, fields["model_type"], "Every reported field should be kept")
+		})
+	}
+}
+
+func TestTTS_ModelInfo(t *testing.T) {
+	coqui, runner := newTestTTS(t)
+	runner.respond = func(cmd Command) (Output, error) {
+		return Output{Stdout: []byte(xttsInfoOutput)}, nil
+	}
+
+	info, err := coqui.ModelInfo(context.Background(), tts.PresetXTTSv2)
+	require.NoError(t, err)
+	assert.Equal(t, "CPML", info.License)
+	assert.Equal(t, "10f92b55c512af7a8d39d650547a15a7", info.Fields["model_hash"])
+
+	calls := runner.calls()
+	require.Len(t, calls, 1)
+	assert.Equal(t, []string{argModelInfoByName, "tts_models/multilingual/multi-dataset/xtts_v2"}, calls[0].Args)
+}
+
+func TestTTS_ModelInfoByIndex(t *testing.T) {
+	coqui, runner := newTestTTS(t)
+	runner.respond = func(cmd Command) (Output, error) {
+		return Output{Stdout: []byte(tacotronInfoOutput)}, nil
+	}
+
+	info, err := coqui.ModelInfoByIndex(context.Background(), model.TypeTTS, 3)
+	require.NoError(t, err)
+	assert.Equal(t, "tts_models/en/ljspeech/tacotron2-DDC", info.Name)
+	assert.Equal(t, []string{argModelInfoByIdx, "tts_models/3"}, runner.calls()[0].Args)
+
+	_, err = coqui.ModelInfoByIndex(context.Background(), model.TypeTTS, 0)
+	assert.Error(t, err)
+}
+
+func TestTTS_ModelInfo_NotFound(t *testing.T) {
+	coqui, runner := newTestTTS(t)
+	runner.respond = func(cmd Command) (Output, error) {
+		return Output{Stdout: []byte("model tts_models/en/ljspeech/missing does not exist!\n")}, nil
+	}
+
+	_, err := coqui.ModelInfo(context.Background(), model.Identifier{
+		Category:        model.TypeTTS,
+		CurrentLanguage: model.English,
+		Dataset:         model.DatasetLJSpeech,
+		Model:           "missing",
+	})
+	assert.ErrorIs(t, err, ErrModelNotFound)
+}
+
+func TestTTS_ModelInfo_Backend(t *testing.T) {
+	coqui, runner := newTestTTS(t, WithServer("http://localhost:5002"))
+
+	_, err := coqui.ModelInfoByIndex(context.Background(), model.TypeTTS, 1)
+	assert.ErrorIs(t, err, ErrNotSupported)
+	assert.Empty(t, runner.calls(), "The CLI should not run when a backend is configured")
+}
@@ -512,10 +512,13 @@ var presets = model.ModelList[Model]{
 	},
 }
 
+func init() {
+	model.RegisterPresets(presets.Models...)
+}
+
 // New creates a new custom TTS model identifier.
+// Use model.ParseIdentifier to build one from a model name such as "tts_models/en/ek1/tacotron2".
 func New(base model.BaseModel, dataset model.Dataset, language model.Language) (model.Identifier, error) {
-	// TODO: Need a method to point to a custom model via a path.
-	// IDEA: It would be cool to add a method that splits the Model Name from a string like "tts_models/en/ek1/tacotron2" into its components.
 	return model.NewModel(model.TypeTTS, base, dataset, language)
 }
 
@@ -19,3 +19,19 @@ func TestGetPresets(t *testing.T) {
 	require.NotEmpty(t, models, "GetPresets should not return an empty slice")
 	assert.Equal(t, presets.Models, models, "GetPresets should return the predefined TTSModels slice")
 }
+
+func TestPresetsParse(t *testing.T) {
+	for _, preset := range presets.Models {
+		for _, name := range preset.NameList() {
+			id, err := model.ParseIdentifier(name)
+			require.NoError(t, err, name)
+			assert.Equal(t, preset.Model, id.Model, name)
+			assert.Equal(t, preset.Dataset, id.Dataset, name)
+			assert.False(t, id.IsCustom, "%s should resolve to its preset", name)
+		}
+	}
+
+	id, err := model.ParseIdentifier("tts_models/multilingual/multi-dataset/xtts_v2")
+	require.NoError(t, err)
+	assert.Equal(t, PresetXTTSv2, id)
+}
@@ -223,6 +223,10 @@ var presets = model.ModelList[Model]{
 	},
 }
 
+func init() {
+	model.RegisterPresets(presets.Models...)
+}
+
 // New creates a new vocoder model identifier.
 func New(base model.BaseModel, dataset model.Dataset, language model.Language) (Model, error) {
 	return model.NewModel(model.TypeVocoder, base, dataset, language)
@@ -31,7 +31,7 @@ var (
 	// voice_conversion_models/multilingual/multi-dataset/knnvc
 	PresetMultidataKnnvc = Model{
 		Category:           model.TypeVoiceConversion,
-		Dataset:            model.DatasetVCTK,
+		Dataset:            model.DatasetMultiDataset,
 		Model:              Knnvc,
 		DefaultLanguage:    model.English,
 		CurrentLanguage:    model.English,
@@ -41,7 +41,7 @@ var (
 	// voice_conversion_models/multilingual/multi-dataset/openvoice_v1
 	PresetMultidataOpenVoiceV1 = Model{
 		Category:           model.TypeVoiceConversion,
-		Dataset:            model.DatasetVCTK,
+		Dataset:            model.DatasetMultiDataset,
 		Model:              OpenvoiceV1,
 		DefaultLanguage:    model.English,
 		CurrentLanguage:    model.English,
@@ -51,7 +51,7 @@ var (
 	// voice_conversion_models/multilingual/multi-dataset/openvoice_v2
 	PresetMultidataOpenVoiceV2 = Model{
 		Category:           model.TypeVoiceConversion,
-		Dataset:            model.DatasetVCTK,
+		Dataset:            model.DatasetMultiDataset,
 		Model:              OpenvoiceV2,
 		DefaultLanguage:    model.English,
 		CurrentLanguage:    model.English,
@@ -63,9 +63,16 @@ var (
 var presets = model.ModelList[Model]{
 	Models: []Model{
 		PresetVCTKFreeVC24,
+		PresetMultidataKnnvc,
+		PresetMultidataOpenVoiceV1,
+		PresetMultidataOpenVoiceV2,
 	},
 }
 
+func init() {
+	model.RegisterPresets(presets.Models...)
+}
+
 // New creates a new VoiceConversion model with the specified parameters.
 func New(base model.BaseModel, dataset model.Dataset, language model.Language) (Model, error) {
 	return model.NewModel(model.TypeVoiceConversion, base, dataset, language)
@@ -1,9 +1,14 @@
 package coqui
 
 import (
+	"log/slog"
+	"net/http"
+
+	"github.com/pixellini/go-coqui/audio"
 	"github.com/pixellini/go-coqui/model"
 	"github.com/pixellini/go-coqui/models/vocoder"
 	"github.com/pixellini/go-coqui/models/voiceconversion"
+	"github.com/pixellini/go-coqui/voices"
 )
 
 // Option defines an interface for TTS configuration options.
@@ -60,9 +65,13 @@ func WithVocoderLanguage(language model.Language) Option {
 }
 
 // WithVoiceConversion sets a voice conversion model to use alongside the TTS model.
-// TODO: Implement proper handling for voice conversion models.
-func WithVoiceConversion(vcModel voiceconversion.Model) Option {
-	return nil
+// With target recordings, synthesized speech is converted into their voice before it's written,
+// so a model without voice cloning can speak in any voice. Without them, the model is only used by Convert.
+// Conversion runs the tts command, so converting synthesized speech isn't supported with WithWorker or WithServer.
+func WithVoiceConversion(vcModel voiceconversion.Model, targetWavs ...string) Option {
+	return optionFunc(func(t *TTS) error {
+		return t.SetCurrentVoiceConversion(vcModel, targetWavs...)
+	})
 }
 
 // WithSpeaker sets the speaker for TTS synthesis.
@@ -74,12 +83,22 @@ func WithSpeaker(speaker string) Option {
 }
 
 // WithSpeakerSample sets the speaker sample file path for XTTS.
+// The file must exist and be a WAV file.
 func WithSpeakerSample(path string) Option {
 	return optionFunc(func(t *TTS) error {
 		return t.SetCurrentSpeakerSample(path)
 	})
 }
 
+// WithSpeakerSamples sets several speaker sample files for voice cloning.
+// Coqui averages the voice of every sample, so several short, clean clips usually clone better than one long one.
+// Each file must exist and be a WAV file.
+func WithSpeakerSamples(paths ...string) Option {
+	return optionFunc(func(t *TTS) error {
+		return t.SetCurrentSpeakerSamples(paths...)
+	})
+}
+
 // WithSpeakerIndex sets the speaker index identifier for VITS.
 func WithSpeakerIndex(idx string) Option {
 	return optionFunc(func(t *TTS) error {
@@ -108,3 +127,200 @@ func WithMaxRetries(mr int) Option {
 		return t.SetCurrentMaxRetries(mr)
 	})
 }
+
+// WithRetryPolicy sets how failed synthesis attempts are retried.
+// Use this to configure backoff between attempts, a limit on the total time spent retrying,
+// and which failures are worth retrying.
+func WithRetryPolicy(p RetryPolicy) Option {
+	return optionFunc(func(t *TTS) error {
+		return t.SetCurrentRetryPolicy(p)
+	})
+}
+
+// WithLogger sets the logger synthesis logs are written to.
+// Logs include the model, attempt, duration and output path.
+// The library is silent when no logger is set.
+func WithLogger(l *slog.Logger) Option {
+	return optionFunc(func(t *TTS) error {
+		return t.SetCurrentLogger(l)
+	})
+}
+
+// WithTextLogging includes the text being synthesized, and the command output that echoes it, in logs.
+// By default only the length of the text is logged.
+func WithTextLogging(enabled bool) Option {
+	return optionFunc(func(t *TTS) error {
+		return t.SetCurrentTextLogging(enabled)
+	})
+}
+
+// WithWorker keeps a Python worker process running between calls, so the model is only loaded once.
+// The worker runs the bundled script with the interpreter set by WithPython (python3 by default)
+// and is started on first use. Call TTS.Close to stop it.
+func WithWorker() Option {
+	return optionFunc(func(t *TTS) error {
+		return t.SetCurrentWorker()
+	})
+}
+
+// WithWorkerCommand is like WithWorker but runs the given command instead of the bundled script.
+// The command must speak the same framed JSON protocol as the bundled script.
+func WithWorkerCommand(name string, args ...string) Option {
+	return optionFunc(func(t *TTS) error {
+		return t.SetCurrentWorker(append([]string{name}, args...)...)
+	})
+}
+
+// WithServer sends synthesis requests to a running Coqui tts-server (e.g. "http://gpu-box:5002")
+// instead of spawning the CLI. The model and vocoder are fixed when the server starts, so the ones configured here
+// are ignored and left out of SynthesisResult; only the speaker index and language are passed along with each request.
+// Use WithHTTPClient to set a timeout.
+func WithServer(baseURL string) Option {
+	return optionFunc(func(t *TTS) error {
+		return t.SetCurrentServer(baseURL)
+	})
+}
+
+// WithHTTPClient sets the client requests to the tts-server are sent with.
+// http.DefaultClient is used otherwise, which has no timeout, so a hung server can only be abandoned by cancelling the context.
+func WithHTTPClient(c *http.Client) Option {
+	return optionFunc(func(t *TTS) error {
+		return t.SetCurrentHTTPClient(c)
+	})
+}
+
+// WithChunkPolicy sets how long text is split into chunks before synthesis,
+// and how much silence is inserted between sentences and paragraphs.
+// Setting it turns chunking on for the tts command and tts-server, which only the worker does by default.
+func WithChunkPolicy(p ChunkPolicy) Option {
+	return optionFunc(func(t *TTS) error {
+		return t.SetCurrentChunkPolicy(p)
+	})
+}
+
+// WithTrimSilence trims leading and trailing silence from every synthesized clip,
+// replacing it with the fixed padding in opts.
+func WithTrimSilence(opts audio.TrimOptions) Option {
+	return optionFunc(func(t *TTS) error {
+		return t.SetCurrentTrimSilence(opts)
+	})
+}
+
+// WithOutputSampleRate resamples every synthesized clip to rate, e.g. 8000 or 16000 for telephony or 48000 for video,
+// so results arrive at the same rate whichever model produced them.
+func WithOutputSampleRate(rate int) Option {
+	return optionFunc(func(t *TTS) error {
+		return t.SetCurrentOutputSampleRate(rate)
+	})
+}
+
+// WithOutputChannels converts every synthesized clip to mono (1) or stereo (2).
+func WithOutputChannels(channels int) Option {
+	return optionFunc(func(t *TTS) error {
+		return t.SetCurrentOutputChannels(channels)
+	})
+}
+
+// WithOutputFormat encodes synthesized audio in format, whatever the output file extension.
+// Without it, the format is taken from the extension: ".wav", ".flac", ".pcm"/".raw", ".mp3", ".opus", ".ulaw" or ".alaw".
+// G.711 in a WAV file (FormatMuLawWAV, FormatALawWAV) can only be selected here or per call with SynthesizeAs.
+// MP3 and Opus need ffmpeg, unless another encoder is registered with WithEncoder.
+// A format without a built-in encoder must be registered with WithEncoder first.
+func WithOutputFormat(format OutputFormat) Option {
+	return optionFunc(func(t *TTS) error {
+		return t.SetCurrentOutputFormat(format)
+	})
+}
+
+// WithEncoder registers the encoder used for format, replacing the built-in one or adding a new format.
+// Use it to encode with a different program, e.g. opusenc instead of ffmpeg.
+func WithEncoder(format OutputFormat, enc Encoder) Option {
+	return optionFunc(func(t *TTS) error {
+		return t.SetCurrentEncoder(format, enc)
+	})
+}
+
+// WithLoudness normalizes every synthesized clip to the target integrated loudness (EBU R128)
+// and limits its true peak, so switching models doesn't change the output volume.
+func WithLoudness(target audio.LoudnessTarget) Option {
+	return optionFunc(func(t *TTS) error {
+		return t.SetCurrentLoudness(target)
+	})
+}
+
+// WithTemperature sets how varied the synthesized voice sounds, e.g. 0.75 for XTTS.
+// Coqui only accepts it through the worker, so it requires WithWorker.
+func WithTemperature(temperature float64) Option {
+	return optionFunc(func(t *TTS) error {
+		return t.SetCurrentTemperature(temperature)
+	})
+}
+
+// WithSpeed sets the speaking rate, where 1 is normal speed.
+// Coqui only accepts it through the worker, so it requires WithWorker.
+func WithSpeed(speed float64) Option {
+	return optionFunc(func(t *TTS) error {
+		return t.SetCurrentSpeed(speed)
+	})
+}
+
+// WithVoices sets the library of voice profiles WithVoice looks voices up in.
+func WithVoices(lib *voices.Library) Option {
+	return optionFunc(func(t *TTS) error {
+		return t.SetCurrentVoices(lib)
+	})
+}
+
+// WithVoice applies the named voice profile: its speaker samples, model, language, speaker index, temperature and speed.
+// The voice is looked up in the library set by WithVoices, which must come first.
+func WithVoice(name string) Option {
+	return optionFunc(func(t *TTS) error {
+		return t.SetCurrentVoice(name)
+	})
+}
+
+// WithSamplePolicy sets the thresholds ValidateSpeakerSample warns about and rejects speaker samples with.
+func WithSamplePolicy(p SamplePolicy) Option {
+	return optionFunc(func(t *TTS) error {
+		return t.SetCurrentSamplePolicy(p)
+	})
+}
+
+// WithRunner sets the Runner used to execute Coqui TTS commands.
+// Use this to swap the default subprocess execution for a fake in tests or a custom wrapper.
+func WithRunner(r Runner) Option {
+	return optionFunc(func(t *TTS) error {
+		return t.SetCurrentRunner(r)
+	})
+}
+
+// WithExecutable sets the path to the Coqui TTS executable.
+// Use this when "tts" isn't on PATH, e.g. when it lives in a virtualenv's bin directory.
+func WithExecutable(path string) Option {
+	return optionFunc(func(t *TTS) error {
+		return t.SetCurrentExecutable(path)
+	})
+}
+
+// WithPython runs Coqui TTS as "python -m TTS.bin.synthesize" using the given interpreter.
+// This takes precedence over WithExecutable.
+func WithPython(interpreter string) Option {
+	return optionFunc(func(t *TTS) error {
+		return t.SetCurrentPython(interpreter)
+	})
+}
+
+// WithWorkingDir sets the working directory of the Coqui TTS process.
+func WithWorkingDir(dir string) Option {
+	return optionFunc(func(t *TTS) error {
+		return t.SetCurrentWorkingDir(dir)
+	})
+}
+
+// WithEnv adds environment variables in "KEY=value" form to the Coqui TTS process.
+// They are added on top of the current process environment.
+func WithEnv(vars ...string) Option {
+	return optionFunc(func(t *TTS) error {
+		return t.SetCurrentEnv(vars...)
+	})
+}
@@ -1,10 +1,14 @@
 package coqui
 
 import (
+	"log/slog"
 	"os"
 	"testing"
+	"time"
 
+	"github.com/pixellini/go-coqui/audio"
 	"github.com/pixellini/go-coqui/model"
+	"github.com/pixellini/go-coqui/models/voiceconversion"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -26,6 +30,7 @@ func TestOptions(t *testing.T) {
 	tmpFile, err := os.CreateTemp("", "test-model-*.ckpt")
 	require.NoError(t, err)
 	t.Cleanup(func() { os.Remove(tmpFile.Name()) })
+	sample := writeTargets(t, "sample.wav")[0]
 
 	tests := []struct {
 		name   string
@@ -83,9 +88,37 @@ func TestOptions(t *testing.T) {
 		},
 		{
 			name:   "WithSpeakerSample",
-			option: WithSpeakerSample("/path/to/sample.wav"),
+			option: WithSpeakerSample(sample),
 			check: func(t *testing.T, tts *TTS) {
-				assert.Equal(t, "/path/to/sample.wav", tts.speakerSample, "WithSpeakerSample should set the speakerSample field")
+				assert.Equal(t, []string{sample}, tts.speakerSamples, "WithSpeakerSample should set the speakerSamples field")
+			},
+		},
+		{
+			name:   "WithSpeakerSamples",
+			option: WithSpeakerSamples(writeTargets(t, "one.wav", "two.wav")...),
+			check: func(t *testing.T, tts *TTS) {
+				assert.Len(t, tts.speakerSamples, 2, "WithSpeakerSamples should set every sample")
+			},
+		},
+		{
+			name:   "WithTemperature",
+			option: WithTemperature(0.7),
+			check: func(t *testing.T, tts *TTS) {
+				assert.Equal(t, 0.7, tts.temperature, "WithTemperature should set the temperature field")
+			},
+		},
+		{
+			name:   "WithSpeed",
+			option: WithSpeed(1.25),
+			check: func(t *testing.T, tts *TTS) {
+				assert.Equal(t, 1.25, tts.speed, "WithSpeed should set the speed field")
+			},
+		},
+		{
+			name:   "WithSamplePolicy",
+			option: WithSamplePolicy(SamplePolicy{Reject: SampleThresholds{MinDuration: 10 * time.Second}}),
+			check: func(t *testing.T, tts *TTS) {
+				assert.Equal(t, 10*time.Second, tts.samplePolicy.Reject.MinDuration, "WithSamplePolicy should set the samplePolicy field")
 			},
 		},
 		{
@@ -116,6 +149,121 @@ func TestOptions(t *testing.T) {
 				assert.Equal(t, 3, tts.maxRetries, "WithMaxRetries should set the maxRetries field")
 			},
 		},
+		{
+			name:   "WithRetryPolicy",
+			option: WithRetryPolicy(RetryPolicy{MaxAttempts: 4, InitialBackoff: time.Second}),
+			check: func(t *testing.T, tts *TTS) {
+				assert.Equal(t, time.Second, tts.retryPolicy.InitialBackoff, "WithRetryPolicy should set the retryPolicy field")
+				assert.Equal(t, 4, tts.maxRetries, "WithRetryPolicy should set maxRetries from MaxAttempts")
+			},
+		},
+		{
+			name:   "WithLogger",
+			option: WithLogger(slog.Default()),
+			check: func(t *testing.T, tts *TTS) {
+				assert.Equal(t, slog.Default(), tts.logger, "WithLogger should set the logger field")
+			},
+		},
+		{
+			name:   "WithChunkPolicy",
+			option: WithChunkPolicy(ChunkPolicy{MaxChars: 100, SentencePause: time.Second}),
+			check: func(t *testing.T, tts *TTS) {
+				assert.Equal(t, 100, tts.chunking.MaxChars, "WithChunkPolicy should set the chunking field")
+				assert.Equal(t, time.Second, tts.chunking.SentencePause, "WithChunkPolicy should set the chunking field")
+			},
+		},
+		{
+			name:   "WithTrimSilence",
+			option: WithTrimSilence(audio.TrimOptions{Threshold: -45, PadStart: 50 * time.Millisecond}),
+			check: func(t *testing.T, tts *TTS) {
+				assert.Equal(t, 50*time.Millisecond, tts.trim.PadStart, "WithTrimSilence should set the trim field")
+			},
+		},
+		{
+			name:   "WithOutputSampleRate",
+			option: WithOutputSampleRate(16000),
+			check: func(t *testing.T, tts *TTS) {
+				assert.Equal(t, 16000, tts.outputSampleRate, "WithOutputSampleRate should set the outputSampleRate field")
+			},
+		},
+		{
+			name:   "WithOutputChannels",
+			option: WithOutputChannels(2),
+			check: func(t *testing.T, tts *TTS) {
+				assert.Equal(t, 2, tts.outputChannels, "WithOutputChannels should set the outputChannels field")
+			},
+		},
+		{
+			name:   "WithLoudness",
+			option: WithLoudness(audio.LoudnessTarget{Integrated: -23, TruePeak: -1}),
+			check: func(t *testing.T, tts *TTS) {
+				assert.Equal(t, -23.0, tts.loudness.Integrated, "WithLoudness should set the loudness field")
+			},
+		},
+		{
+			name:   "WithOutputFormat",
+			option: WithOutputFormat(".FLAC"),
+			check: func(t *testing.T, tts *TTS) {
+				assert.Equal(t, FormatFLAC, tts.outputFormat, "WithOutputFormat should normalize and set the outputFormat field")
+			},
+		},
+		{
+			name:   "WithEncoder",
+			option: WithEncoder("wma", &CommandEncoder{Name: "ffmpeg"}),
+			check: func(t *testing.T, tts *TTS) {
+				assert.Equal(t, &CommandEncoder{Name: "ffmpeg"}, tts.encoders["wma"], "WithEncoder should register the encoder")
+			},
+		},
+		{
+			name:   "WithVoiceConversion",
+			option: WithVoiceConversion(voiceconversion.PresetMultidataKnnvc),
+			check: func(t *testing.T, tts *TTS) {
+				assert.Equal(t, voiceconversion.PresetMultidataKnnvc, tts.voiceConversion, "WithVoiceConversion should set the voiceConversion field")
+				assert.Empty(t, tts.vcTargets, "Without targets, synthesized speech should not be converted")
+			},
+		},
+		{
+			name:   "WithTextLogging",
+			option: WithTextLogging(true),
+			check: func(t *testing.T, tts *TTS) {
+				assert.True(t, tts.logText, "WithTextLogging should set the logText field")
+			},
+		},
+		{
+			name:   "WithRunner",
+			option: WithRunner(ExecRunner{}),
+			check: func(t *testing.T, tts *TTS) {
+				assert.Equal(t, ExecRunner{}, tts.runner, "WithRunner should set the runner field")
+			},
+		},
+		{
+			name:   "WithExecutable",
+			option: WithExecutable("/opt/venv/bin/tts"),
+			check: func(t *testing.T, tts *TTS) {
+				assert.Equal(t, "/opt/venv/bin/tts", tts.executable, "WithExecutable should set the executable field")
+			},
+		},
+		{
+			name:   "WithPython",
+			option: WithPython("/opt/venv/bin/python"),
+			check: func(t *testing.T, tts *TTS) {
+				assert.Equal(t, "/opt/venv/bin/python", tts.python, "WithPython should set the python field")
+			},
+		},
+		{
+			name:   "WithWorkingDir",
+			option: WithWorkingDir(os.TempDir()),
+			check: func(t *testing.T, tts *TTS) {
+				assert.Equal(t, os.TempDir(), tts.workingDir, "WithWorkingDir should set the workingDir field")
+			},
+		},
+		{
+			name:   "WithEnv",
+			option: WithEnv("HF_HOME=/cache", "COQUI_TOS_AGREED=1"),
+			check: func(t *testing.T, tts *TTS) {
+				assert.Equal(t, []string{"HF_HOME=/cache", "COQUI_TOS_AGREED=1"}, tts.env, "WithEnv should set the env field")
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -130,3 +278,8 @@ func TestOptions(t *testing.T) {
 		})
 	}
 }
+
+func TestWithEnv_Invalid(t *testing.T) {
+	err := WithEnv("NOT_A_PAIR").apply(&TTS{})
+	assert.Error(t, err, "WithEnv should reject variables without a value")
+}
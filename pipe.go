@@ -0,0 +1,74 @@
+package coqui
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+)
+
+// runPipe executes the Coqui TTS command with --pipe_out and returns the WAV audio written to stdout.
+// Coqui still writes a file to --out_path when piping, so it's pointed at a temporary file that is removed afterwards.
+// If a backend is configured, it is used instead.
+func (t TTS) runPipe(ctx context.Context, text string) ([]byte, attemptStats, error) {
+	if t.backend != nil {
+		return t.backend.synthesize(ctx, t, text)
+	}
+
+	if t.styled() {
+		return nil, attemptStats{}, fmt.Errorf("%w: temperature and speed with the tts command, use WithWorker", ErrNotSupported)
+	}
+
+	tmp, err := os.CreateTemp("", "go-coqui-*.wav")
+	if err != nil {
+		return nil, attemptStats{}, fmt.Errorf("failed to create temporary output file: %w", err)
+	}
+	tmp.Close()
+	defer os.Remove(tmp.Name())
+
+	args := toArgs(t)
+	args = append(args,
+		argText, text,
+		argOutPath, tmp.Name(),
+		argPipeOut,
+	)
+
+	out, err := t.runCommand(ctx, args)
+	if err != nil {
+		t.log().LogAttrs(ctx, slog.LevelDebug, "TTS command failed", t.outputAttr(out.Combined()))
+		return nil, attemptStats{}, newCommandError(out, err)
+	}
+
+	audio, err := extractWav(out.Stdout)
+	if err != nil {
+		return nil, attemptStats{}, fmt.Errorf("TTS command produced no audio: %w", err)
+	}
+	return audio, parseStats(out.Combined()), nil
+}
+
+// extractWav returns the WAV file contained in stdout.
+// Coqui and its dependencies may print log lines to stdout around the audio,
+// so anything before the RIFF header or after the length it declares is dropped.
+func extractWav(stdout []byte) ([]byte, error) {
+	for offset := 0; ; {
+		idx := bytes.Index(stdout[offset:], []byte("RIFF"))
+		if idx == -1 {
+			return nil, errors.New("no WAV header found in output")
+		}
+		start := offset + idx
+
+		// A RIFF header is "RIFF", a 4 byte length, then "WAVE".
+		if len(stdout)-start >= 12 && string(stdout[start+8:start+12]) == "WAVE" {
+			audio := stdout[start:]
+			size := int64(binary.LittleEndian.Uint32(audio[4:8])) + 8
+			if size <= int64(len(audio)) {
+				audio = audio[:size]
+			}
+			return audio, nil
+		}
+		offset = start + 4
+	}
+}
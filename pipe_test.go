@@ -0,0 +1,87 @@
+package coqui
+
+import (
+	"bytes"
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSynthesizeBytes(t *testing.T) {
+	coqui, runner := newTestTTS(t)
+	wav := fakeWav(24000, 1, 240)
+
+	var outPath string
+	runner.respond = func(cmd Command) (Output, error) {
+		outPath = argValue(cmd.Args, argOutPath)
+		assert.FileExists(t, outPath, "Coqui should be given a temporary output file")
+
+		// Log noise around the audio must not end up in the result.
+		stdout := append([]byte(" > Text splitted to sentences.\n"), wav...)
+		stdout = append(stdout, []byte("\n > Processing time: 0.5\n")...)
+		return Output{Stdout: stdout, Stderr: []byte("warnings")}, nil
+	}
+
+	audio, err := coqui.SynthesizeBytes(context.Background(), "Hello world")
+	require.NoError(t, err)
+	assert.Equal(t, wav, audio)
+
+	calls := runner.calls()
+	require.Len(t, calls, 1)
+	assert.True(t, slices.Contains(calls[0].Args, argPipeOut), "SynthesizeBytes should pass --pipe_out")
+	assert.Equal(t, "Hello world", argValue(calls[0].Args, argText))
+	assert.NoFileExists(t, outPath, "The temporary output file should be removed")
+}
+
+func TestSynthesizeTo(t *testing.T) {
+	coqui, runner := newTestTTS(t)
+	wav := fakeWav(22050, 1, 100)
+	runner.respond = func(cmd Command) (Output, error) {
+		return Output{Stdout: wav}, nil
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, coqui.SynthesizeTo(context.Background(), "Hello world", &buf))
+	assert.Equal(t, wav, buf.Bytes())
+}
+
+func TestSynthesizeBytes_NoAudio(t *testing.T) {
+	coqui, runner := newTestTTS(t, WithMaxRetries(1))
+	runner.respond = func(cmd Command) (Output, error) {
+		return Output{Stdout: []byte("only logs")}, nil
+	}
+
+	_, err := coqui.SynthesizeBytes(context.Background(), "Hello world")
+	assert.Error(t, err, "SynthesizeBytes should fail when stdout contains no WAV data")
+}
+
+func TestExtractWav(t *testing.T) {
+	wav := fakeWav(16000, 1, 10)
+
+	tests := []struct {
+		name     string
+		stdout   []byte
+		expected []byte
+		wantErr  bool
+	}{
+		{name: "Only audio", stdout: wav, expected: wav},
+		{name: "Leading noise", stdout: append([]byte("RIFF is mentioned in a log line\n"), wav...), expected: wav},
+		{name: "Trailing noise", stdout: append(slices.Clone(wav), []byte("done\n")...), expected: wav},
+		{name: "No audio", stdout: []byte("nothing here"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			audio, err := extractWav(tt.stdout)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, audio)
+		})
+	}
+}
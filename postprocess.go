@@ -0,0 +1,97 @@
+package coqui
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/pixellini/go-coqui/audio"
+)
+
+// postProcessing reports whether any post-processing is needed for audio delivered in format.
+func (t TTS) postProcessing(format OutputFormat) bool {
+	if _, ok := g711Formats[format]; ok {
+		return true
+	}
+	return t.trim != (audio.TrimOptions{}) || t.outputChannels != 0 || t.outputSampleRate != 0 ||
+		t.loudness != (audio.LoudnessTarget{})
+}
+
+// postProcess applies the configured post-processing to a clip delivered in format.
+// Steps run in a fixed order: silence is trimmed, the channels and sample rate are converted,
+// then the loudness is normalized so it's measured on the audio as it will be delivered.
+// G.711 formats are always converted to 8kHz mono, whatever rate and channels are configured.
+func (t TTS) postProcess(clip *audio.Clip, format OutputFormat) (*audio.Clip, error) {
+	channels, sampleRate := t.outputChannels, t.outputSampleRate
+	law, g711 := g711Formats[format]
+	if g711 {
+		channels, sampleRate = 1, telephonySampleRate
+	}
+
+	var err error
+	if t.trim != (audio.TrimOptions{}) {
+		if clip, err = audio.Trim(clip, t.trim); err != nil {
+			return nil, fmt.Errorf("failed to trim silence: %w", err)
+		}
+	}
+	if channels != 0 {
+		if clip, err = audio.Remix(clip, channels); err != nil {
+			return nil, fmt.Errorf("failed to convert channels: %w", err)
+		}
+	}
+	if sampleRate != 0 {
+		if clip, err = audio.Resample(clip, sampleRate); err != nil {
+			return nil, fmt.Errorf("failed to resample audio: %w", err)
+		}
+	}
+	if t.loudness != (audio.LoudnessTarget{}) {
+		if clip, err = audio.Normalize(clip, t.loudness); err != nil {
+			return nil, fmt.Errorf("failed to normalize loudness: %w", err)
+		}
+	}
+	if g711 {
+		clip.Format = law
+	}
+	return clip, nil
+}
+
+// finishBytes post-processes WAV audio and encodes it in format.
+// WAV audio that needs no post-processing is returned untouched.
+func (t TTS) finishBytes(ctx context.Context, wav []byte, format OutputFormat) ([]byte, error) {
+	if format == FormatWAV && !t.postProcessing(format) {
+		return wav, nil
+	}
+
+	clip, err := audio.DecodeBytes(wav)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode generated audio: %w", err)
+	}
+	if clip, err = t.postProcess(clip, format); err != nil {
+		return nil, err
+	}
+	return t.encode(ctx, clip, format)
+}
+
+// finishFile post-processes the WAV file Coqui wrote to wavPath and writes it to outputPath in format.
+// It returns the final audio so the result can describe it whatever format it was written in.
+func (t TTS) finishFile(ctx context.Context, wavPath, outputPath string, format OutputFormat) (*audio.Clip, error) {
+	clip, err := audio.ReadFile(wavPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read generated audio %s: %w", wavPath, err)
+	}
+	if format == FormatWAV && !t.postProcessing(format) {
+		return clip, nil
+	}
+
+	if clip, err = t.postProcess(clip, format); err != nil {
+		return nil, err
+	}
+	data, err := t.encode(ctx, clip, format)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(outputPath, data, 0644); err != nil {
+		return nil, fmt.Errorf("failed to write audio: %w", err)
+	}
+	return clip, nil
+}
@@ -0,0 +1,118 @@
+package coqui
+
+import (
+	"context"
+	"math"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/pixellini/go-coqui/audio"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// toneWav builds a mono 16-bit WAV of a 440Hz sine wave.
+func toneWav(t *testing.T, sampleRate int, amplitude float64, d time.Duration) []byte {
+	t.Helper()
+	clip := &audio.Clip{SampleRate: sampleRate, Channels: 1, Format: audio.PCM16}
+	for i := 0; i < int(d.Seconds()*float64(sampleRate)); i++ {
+		clip.Samples = append(clip.Samples, float32(amplitude*math.Sin(2*math.Pi*440*float64(i)/float64(sampleRate))))
+	}
+	wav, err := clip.Bytes()
+	require.NoError(t, err)
+	return wav
+}
+
+// respondWithWav makes the runner write wav to --out_path and pipe it to stdout.
+func respondWithWav(runner *fakeRunner, wav []byte) {
+	runner.respond = func(cmd Command) (Output, error) {
+		if err := os.WriteFile(argValue(cmd.Args, argOutPath), wav, 0644); err != nil {
+			return Output{}, err
+		}
+		return Output{Stdout: wav}, nil
+	}
+}
+
+func TestSynthesize_Loudness(t *testing.T) {
+	target := audio.LoudnessTarget{Integrated: -16, TruePeak: -1}
+	coqui, runner := newTestTTS(t, WithLoudness(target))
+	respondWithWav(runner, toneWav(t, 24000, 0.05, time.Second))
+
+	result, err := coqui.Synthesize("Hello world", "hello.wav")
+	require.NoError(t, err)
+
+	clip, err := audio.ReadFile(result.OutputPath)
+	require.NoError(t, err)
+	assert.InDelta(t, -16, audio.Loudness(clip), 0.1, "The written file should be normalized")
+	assert.Equal(t, time.Second, result.Duration)
+
+	wav, err := coqui.SynthesizeBytes(context.Background(), "Hello world")
+	require.NoError(t, err)
+	clip, err = audio.DecodeBytes(wav)
+	require.NoError(t, err)
+	assert.InDelta(t, -16, audio.Loudness(clip), 0.1, "Audio returned in memory should be normalized too")
+}
+
+func TestSynthesize_TrimSilence(t *testing.T) {
+	coqui, runner := newTestTTS(t, WithTrimSilence(audio.TrimOptions{Threshold: -45, PadStart: 20 * time.Millisecond, PadEnd: 30 * time.Millisecond}))
+	silence := audio.Silence(24000, 1, audio.PCM16, 400*time.Millisecond)
+	tone, err := audio.DecodeBytes(toneWav(t, 24000, 0.5, 200*time.Millisecond))
+	require.NoError(t, err)
+	clip, err := audio.Concat(silence, tone, silence)
+	require.NoError(t, err)
+	wav, err := clip.Bytes()
+	require.NoError(t, err)
+	respondWithWav(runner, wav)
+
+	result, err := coqui.Synthesize("Hello world", "hello.wav")
+	require.NoError(t, err)
+	assert.Equal(t, 250*time.Millisecond, result.Duration, "Result should describe the trimmed audio")
+}
+
+func TestSynthesize_OutputFormat(t *testing.T) {
+	tests := []struct {
+		name     string
+		rate     int
+		channels int
+	}{
+		{name: "Telephony", rate: 8000, channels: 1},
+		{name: "Video", rate: 48000, channels: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			coqui, runner := newTestTTS(t, WithOutputSampleRate(tt.rate), WithOutputChannels(tt.channels))
+			respondWithWav(runner, toneWav(t, 22050, 0.5, 500*time.Millisecond))
+
+			result, err := coqui.Synthesize("Hello world", "hello.wav")
+			require.NoError(t, err)
+			assert.Equal(t, tt.rate, result.SampleRate)
+			assert.Equal(t, tt.channels, result.Channels)
+			assert.InDelta(t, 500*time.Millisecond, result.Duration, float64(time.Millisecond))
+		})
+	}
+}
+
+func TestWithOutputChannels_Invalid(t *testing.T) {
+	_, err := New(WithRunner(&fakeRunner{}), WithOutputChannels(6))
+	assert.Error(t, err)
+}
+
+func TestSynthesize_NoPostProcessing(t *testing.T) {
+	coqui, runner := newTestTTS(t)
+	wav := toneWav(t, 24000, 0.05, 100*time.Millisecond)
+	respondWithWav(runner, wav)
+
+	result, err := coqui.Synthesize("Hello world", "hello.wav")
+	require.NoError(t, err)
+
+	written, err := os.ReadFile(result.OutputPath)
+	require.NoError(t, err)
+	assert.Equal(t, wav, written, "Audio should be left untouched without post-processing")
+}
+
+func TestWithLoudness_Invalid(t *testing.T) {
+	_, err := New(WithRunner(&fakeRunner{}), WithLoudness(audio.LoudnessTarget{Integrated: 3}))
+	assert.Error(t, err)
+}
@@ -0,0 +1,75 @@
+package coqui
+
+import (
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/pixellini/go-coqui/model"
+)
+
+// SynthesisResult describes the audio produced by a synthesis call.
+type SynthesisResult struct {
+	// OutputPath is the absolute path of the generated audio file.
+	OutputPath string
+	// Format is the format the audio was written in.
+	Format OutputFormat
+	// Duration is the length of the generated audio.
+	Duration time.Duration
+	// SampleRate is the sample rate of the generated audio in Hz.
+	SampleRate int
+	// Channels is the number of audio channels.
+	Channels int
+	// Model is the resolved model name (or model path for custom models).
+	// Empty with WithServer, since the server synthesizes with the model it was started with.
+	Model string
+	// Vocoder is the resolved vocoder name.
+	// Empty when the model's default vocoder was used, or with WithServer.
+	Vocoder string
+	// VoiceConversion is the name of the voice conversion model the audio was converted with.
+	// Empty when the audio wasn't converted.
+	VoiceConversion string
+	// Device is the compute device the synthesis ran on.
+	// "auto" is resolved to the detected device.
+	// Empty with WithServer, since the server runs on its own device.
+	Device model.Device
+	// Attempts is the number of attempts it took to synthesize the audio.
+	Attempts int
+	// StartedAt is when the first attempt started.
+	StartedAt time.Time
+	// Elapsed is the wall-clock time taken, including retries.
+	Elapsed time.Duration
+	// ProcessingTime is the processing time reported by Coqui for the successful attempt.
+	ProcessingTime time.Duration
+	// RealTimeFactor is the real-time factor reported by Coqui for the successful attempt.
+	// Values below 1 mean the audio was generated faster than it plays back.
+	RealTimeFactor float64
+}
+
+var (
+	processingTimeRe = regexp.MustCompile(`Processing time:\s*([0-9.]+)`)
+	realTimeFactorRe = regexp.MustCompile(`Real-time factor:\s*([0-9.]+(?:[eE][-+]?[0-9]+)?)`)
+)
+
+// attemptStats holds the timings reported for a successful synthesis attempt.
+type attemptStats struct {
+	processingTime time.Duration
+	realTimeFactor float64
+}
+
+// parseStats extracts the processing time and real-time factor that Coqui prints after synthesis.
+// Values that aren't present are left at zero.
+func parseStats(output []byte) attemptStats {
+	var stats attemptStats
+	if m := processingTimeRe.FindSubmatch(output); m != nil {
+		if seconds, err := strconv.ParseFloat(string(m[1]), 64); err == nil {
+			stats.processingTime = time.Duration(seconds * float64(time.Second))
+		}
+	}
+	if m := realTimeFactorRe.FindSubmatch(output); m != nil {
+		if rtf, err := strconv.ParseFloat(string(m[1]), 64); err == nil {
+			stats.realTimeFactor = rtf
+		}
+	}
+	return stats
+}
@@ -0,0 +1,50 @@
+package coqui
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pixellini/go-coqui/model"
+	"github.com/pixellini/go-coqui/models/vocoder"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSynthesize_Result(t *testing.T) {
+	coqui, runner := newTestTTS(t, WithVocoder(vocoder.PresetHifiganV2VCTK))
+	runner.respond = func(cmd Command) (Output, error) {
+		if _, err := writeFakeWav(cmd); err != nil {
+			return Output{}, err
+		}
+		stdout := " > Text splitted to sentences.\n > Processing time: 1.5\n > Real-time factor: 0.25\n"
+		return Output{Stdout: []byte(stdout)}, nil
+	}
+
+	result, err := coqui.Synthesize("Hello world", "hello.wav")
+	require.NoError(t, err)
+
+	assert.True(t, filepath.IsAbs(result.OutputPath), "OutputPath should be absolute")
+	assert.Equal(t, "hello.wav", filepath.Base(result.OutputPath))
+	assert.Equal(t, 100*time.Millisecond, result.Duration, "2205 frames at 22050Hz should be 100ms")
+	assert.Equal(t, 22050, result.SampleRate)
+	assert.Equal(t, 1, result.Channels)
+	assert.Equal(t, coqui.Name(), result.Model)
+	assert.Equal(t, "vocoder_models/en/vctk/hifigan_v2", result.Vocoder)
+	assert.Equal(t, model.DeviceCPU, result.Device)
+	assert.Equal(t, 1, result.Attempts)
+	assert.False(t, result.StartedAt.IsZero())
+	assert.Positive(t, result.Elapsed)
+	assert.Equal(t, 1500*time.Millisecond, result.ProcessingTime)
+	assert.Equal(t, 0.25, result.RealTimeFactor)
+}
+
+func TestParseStats(t *testing.T) {
+	stats := parseStats([]byte(" > Processing time: 0.8342\n > Real-time factor: 1.2e-01\n"))
+	assert.Equal(t, time.Duration(0.8342*float64(time.Second)), stats.processingTime)
+	assert.InDelta(t, 0.12, stats.realTimeFactor, 1e-9)
+
+	stats = parseStats([]byte("no stats here"))
+	assert.Zero(t, stats.processingTime)
+	assert.Zero(t, stats.realTimeFactor)
+}
@@ -0,0 +1,143 @@
+package coqui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// RetryPolicy controls how failed synthesis attempts are retried.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts, including the first one.
+	// Zero keeps the value set by WithMaxRetries.
+	MaxAttempts int
+	// InitialBackoff is how long to wait before the first retry.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the wait between attempts. Zero means no cap.
+	MaxBackoff time.Duration
+	// Multiplier is applied to the backoff after every attempt.
+	// Values below 1 are treated as 1, i.e. a constant backoff.
+	Multiplier float64
+	// Jitter randomises each backoff by up to this fraction (0-1) in either direction,
+	// so that many clients failing at once don't retry in lockstep.
+	Jitter float64
+	// MaxElapsed caps the total time spent on attempts and backoff.
+	// No further attempts are started once it would be exceeded. Zero means no limit.
+	MaxElapsed time.Duration
+	// Retryable decides whether a failure should be retried.
+	// If nil, IsRetryable is used.
+	Retryable func(error) bool
+}
+
+const (
+	defaultInitialBackoff = 500 * time.Millisecond
+	defaultMaxBackoff     = 10 * time.Second
+	defaultMultiplier     = 2
+	defaultJitter         = 0.2
+)
+
+// DefaultRetryPolicy returns the policy used when no RetryPolicy is configured.
+// Backs off exponentially from 500ms up to 10s with 20% jitter, and doesn't retry permanent failures.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		InitialBackoff: defaultInitialBackoff,
+		MaxBackoff:     defaultMaxBackoff,
+		Multiplier:     defaultMultiplier,
+		Jitter:         defaultJitter,
+		Retryable:      IsRetryable,
+	}
+}
+
+// IsRetryable reports whether a failed attempt is worth retrying.
+// Context cancellation and permanent failures (see IsPermanent) are not retried;
+// transient and unrecognised failures are.
+func IsRetryable(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	return !IsPermanent(err)
+}
+
+// Validate checks that the policy's values are usable.
+func (p RetryPolicy) Validate() error {
+	if p.MaxAttempts < 0 {
+		return fmt.Errorf("max attempts cannot be negative")
+	}
+	if p.InitialBackoff < 0 || p.MaxBackoff < 0 || p.MaxElapsed < 0 {
+		return fmt.Errorf("retry durations cannot be negative")
+	}
+	if p.Jitter < 0 || p.Jitter > 1 {
+		return fmt.Errorf("jitter must be between 0 and 1")
+	}
+	return nil
+}
+
+// retryable reports whether err should be retried under this policy.
+func (p RetryPolicy) retryable(err error) bool {
+	if p.Retryable == nil {
+		return IsRetryable(err)
+	}
+	return p.Retryable(err)
+}
+
+// backoff returns how long to wait after the given attempt (starting at 1) failed.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	multiplier := math.Max(p.Multiplier, 1)
+	d := float64(p.InitialBackoff) * math.Pow(multiplier, float64(attempt-1))
+	if p.MaxBackoff > 0 {
+		d = math.Min(d, float64(p.MaxBackoff))
+	}
+	if p.Jitter > 0 {
+		d *= 1 + p.Jitter*(2*rand.Float64()-1)
+	}
+	return time.Duration(d)
+}
+
+// retry calls attempt until it succeeds, the failure isn't retryable, or the retry policy is exhausted.
+// Returns immediately when ctx is done, including while waiting between attempts.
+// Returns the number of attempts made and the error of the last attempt.
+func (t TTS) retry(ctx context.Context, attempt func() error) (int, error) {
+	policy := t.retryPolicy
+	maxAttempts := max(t.maxRetries, 1)
+	start := time.Now()
+
+	for i := 1; ; i++ {
+		attemptStart := time.Now()
+		err := attempt()
+		if err == nil {
+			return i, nil
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return i, fmt.Errorf("%w: %w", ctxErr, err)
+		}
+
+		t.log().LogAttrs(ctx, slog.LevelWarn, "synthesis attempt failed",
+			slog.String("model", t.modelName()),
+			slog.Int("attempt", i),
+			slog.Int("max_attempts", maxAttempts),
+			slog.Duration("duration", time.Since(attemptStart)),
+			slog.Any("error", err),
+		)
+
+		if i >= maxAttempts || !policy.retryable(err) {
+			return i, err
+		}
+
+		wait := policy.backoff(i)
+		if policy.MaxElapsed > 0 && time.Since(start)+wait >= policy.MaxElapsed {
+			return i, err
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return i, fmt.Errorf("%w: %w", ctx.Err(), err)
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package coqui
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// failingRunner fails the first failures commands with the given stderr, then writes a WAV.
+func failingRunner(runner *fakeRunner, failures int, stderr string) {
+	calls := 0
+	runner.respond = func(cmd Command) (Output, error) {
+		calls++
+		if calls <= failures {
+			return Output{Stderr: []byte(stderr)}, errors.New("exit status 1")
+		}
+		return writeFakeWav(cmd)
+	}
+}
+
+func TestRetry_TransientFailureIsRetried(t *testing.T) {
+	coqui, runner := newTestTTS(t, WithRetryPolicy(RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+		Multiplier:     2,
+	}))
+	failingRunner(runner, 2, "torch.cuda.OutOfMemoryError: CUDA out of memory.")
+
+	result, err := coqui.Synthesize("Hello world", "hello.wav")
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.Attempts)
+	assert.Len(t, runner.calls(), 3)
+}
+
+func TestRetry_PermanentFailureFailsFast(t *testing.T) {
+	coqui, runner := newTestTTS(t, WithMaxRetries(5))
+	failingRunner(runner, 5, "Model `tts_models/en/foo/bar` not found in the model list.")
+
+	_, err := coqui.Synthesize("Hello world", "hello.wav")
+	assert.ErrorIs(t, err, ErrModelNotFound)
+	assert.Len(t, runner.calls(), 1, "A bad model name should not be retried")
+}
+
+func TestRetry_CustomPredicate(t *testing.T) {
+	coqui, runner := newTestTTS(t, WithRetryPolicy(RetryPolicy{
+		MaxAttempts: 5,
+		Retryable:   IsTransient,
+	}))
+	failingRunner(runner, 5, "something unexpected")
+
+	_, err := coqui.Synthesize("Hello world", "hello.wav")
+	assert.Error(t, err)
+	assert.Len(t, runner.calls(), 1, "Only transient failures should be retried by the predicate")
+}
+
+func TestRetry_ContextCancelledDuringBackoff(t *testing.T) {
+	coqui, runner := newTestTTS(t, WithRetryPolicy(RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: time.Hour,
+	}))
+	failingRunner(runner, 3, "CUDA out of memory")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := coqui.SynthesizeContext(ctx, "Hello world", "hello.wav")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.ErrorIs(t, err, ErrOutOfMemory, "The last attempt's error should be kept")
+	assert.Less(t, time.Since(start), time.Second, "Synthesis should return as soon as the context is done")
+	assert.Len(t, runner.calls(), 1)
+}
+
+func TestRetry_MaxElapsed(t *testing.T) {
+	coqui, runner := newTestTTS(t, WithRetryPolicy(RetryPolicy{
+		MaxAttempts:    10,
+		InitialBackoff: 50 * time.Millisecond,
+		MaxElapsed:     10 * time.Millisecond,
+	}))
+	failingRunner(runner, 10, "CUDA out of memory")
+
+	_, err := coqui.Synthesize("Hello world", "hello.wav")
+	assert.ErrorIs(t, err, ErrOutOfMemory)
+	assert.Len(t, runner.calls(), 1, "No attempt should start once MaxElapsed would be exceeded")
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	p := RetryPolicy{
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     time.Second,
+		Multiplier:     2,
+	}
+	assert.Equal(t, 100*time.Millisecond, p.backoff(1))
+	assert.Equal(t, 200*time.Millisecond, p.backoff(2))
+	assert.Equal(t, 400*time.Millisecond, p.backoff(3))
+	assert.Equal(t, time.Second, p.backoff(10), "Backoff should be capped at MaxBackoff")
+
+	p.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		d := p.backoff(1)
+		assert.GreaterOrEqual(t, d, 50*time.Millisecond)
+		assert.LessOrEqual(t, d, 150*time.Millisecond)
+	}
+}
+
+func TestRetryPolicy_Validate(t *testing.T) {
+	assert.NoError(t, DefaultRetryPolicy().Validate())
+	assert.Error(t, RetryPolicy{MaxAttempts: -1}.Validate())
+	assert.Error(t, RetryPolicy{InitialBackoff: -time.Second}.Validate())
+	assert.Error(t, RetryPolicy{Jitter: 2}.Validate())
+}
+
+func TestIsRetryable(t *testing.T) {
+	assert.True(t, IsRetryable(errors.New("unknown failure")))
+	assert.True(t, IsRetryable(ErrModelDownloadFailed))
+	assert.False(t, IsRetryable(ErrSpeakerRequired))
+	assert.False(t, IsRetryable(context.Canceled))
+}
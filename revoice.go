@@ -0,0 +1,108 @@
+package coqui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// Revoice re-voices the speech in referenceWav as targetSpeaker, one of the speakers of the configured
+// multi-speaker model (e.g. "p225" for tts_models/en/vctk/vits), and saves it to outputPath.
+// The words and timing of the recording are kept, so no transcript is needed.
+// The speaker of the recording is worked out with the model's speaker encoder; use RevoiceFrom if it's one of the model's speakers.
+// Like Synthesize, outputPath is in the output directory, its extension picks the output format,
+// and the configured post-processing is applied.
+// Returns an error if the output file already exists.
+// Re-voicing runs the tts command, so it isn't supported with WithWorker or WithServer.
+func (t TTS) Revoice(ctx context.Context, referenceWav, targetSpeaker, outputPath string) (*SynthesisResult, error) {
+	return t.RevoiceFrom(ctx, referenceWav, "", targetSpeaker, outputPath)
+}
+
+// RevoiceFrom is like Revoice for a recording of referenceSpeaker, one of the model's own speakers.
+func (t TTS) RevoiceFrom(ctx context.Context, referenceWav, referenceSpeaker, targetSpeaker, outputPath string) (*SynthesisResult, error) {
+	if referenceWav == "" {
+		return nil, errors.New("reference audio path cannot be empty")
+	}
+	if targetSpeaker == "" {
+		return nil, errors.New("target speaker cannot be empty")
+	}
+	if err := t.requireCommand("re-voicing"); err != nil {
+		return nil, err
+	}
+	if t.model.SupportsVoiceCloning && !t.model.IsCustom {
+		return nil, fmt.Errorf("%w: %s clones voices from samples, re-voicing needs a multi-speaker model like VITS on VCTK", ErrNotSupported, t.modelName())
+	}
+	if err := checkWavs([]string{referenceWav}); err != nil {
+		return nil, err
+	}
+	for _, speaker := range []string{referenceSpeaker, targetSpeaker} {
+		if err := t.checkSpeaker(speaker); err != nil {
+			return nil, err
+		}
+	}
+
+	out, cleanup, err := t.prepareOutput(outputPath)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	t.log().LogAttrs(ctx, slog.LevelDebug, "re-voicing",
+		slog.String("model", t.modelName()),
+		slog.String("reference", referenceWav),
+		slog.String("speaker", targetSpeaker),
+		slog.String("output_path", out.path),
+	)
+
+	startedAt := time.Now()
+	var stats attemptStats
+	attempts, err := t.retry(ctx, func() error {
+		cmdOut, err := t.runCommand(ctx, revoiceArgs(t, referenceWav, referenceSpeaker, targetSpeaker, out.wavPath))
+		if err != nil {
+			t.log().LogAttrs(ctx, slog.LevelDebug, "re-voicing command failed", t.outputAttr(cmdOut.Combined()))
+			return newCommandError(cmdOut, err)
+		}
+		stats = parseStats(cmdOut.Combined())
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	clip, err := t.finishFile(ctx, out.wavPath, out.path, out.format)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := t.newResult(out.path, out.format, clip, stats, attempts, startedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	t.log().LogAttrs(ctx, slog.LevelInfo, "re-voicing complete",
+		slog.String("model", result.Model),
+		slog.Int("attempts", result.Attempts),
+		slog.Duration("duration", result.Elapsed),
+		slog.String("output_path", result.OutputPath),
+	)
+	return result, nil
+}
+
+// revoiceArgs returns the arguments that re-voice referenceWav as targetSpeaker.
+func revoiceArgs(t TTS, referenceWav, referenceSpeaker, targetSpeaker, outputPath string) []string {
+	args := modelArgs(t)
+	if t.vocoder.IsValid() {
+		args = append(args, argVocoderName, t.VocoderName())
+	}
+
+	args = append(args,
+		argReferenceWav, referenceWav,
+		argSpeakerIdx, targetSpeaker,
+	)
+	if referenceSpeaker != "" {
+		args = append(args, argReferenceSpeakerIdx, referenceSpeaker)
+	}
+	return append(args, argOutPath, outputPath)
+}
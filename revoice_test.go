@@ -0,0 +1,72 @@
+package coqui
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pixellini/go-coqui/models/tts"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRevoice(t *testing.T) {
+	coqui, runner := newTestTTS(t, WithModelId(tts.PresetVITSVCTK))
+	respondWithWav(runner, toneWav(t, 22050, 0.5, 400*time.Millisecond))
+	reference := writeTargets(t, "line-12.wav")[0]
+
+	result, err := coqui.Revoice(context.Background(), reference, "p225", "line-12-p225.wav")
+	require.NoError(t, err)
+	assert.Equal(t, "tts_models/en/vctk/vits", result.Model)
+	assert.Equal(t, 400*time.Millisecond, result.Duration)
+
+	calls := runner.calls()
+	require.Len(t, calls, 1)
+	args := calls[0].Args
+	assert.Equal(t, "tts_models/en/vctk/vits", argValue(args, argModelName))
+	assert.Equal(t, reference, argValue(args, argReferenceWav))
+	assert.Equal(t, "p225", argValue(args, argSpeakerIdx))
+	assert.NotContains(t, args, argReferenceSpeakerIdx)
+	assert.NotContains(t, args, argText, "Re-voicing should not need a transcript")
+}
+
+func TestRevoiceFrom(t *testing.T) {
+	coqui, runner := newTestTTS(t, WithModelId(tts.PresetVITSVCTK))
+	respondWithWav(runner, toneWav(t, 22050, 0.5, 100*time.Millisecond))
+	reference := writeTargets(t, "line.wav")[0]
+
+	_, err := coqui.RevoiceFrom(context.Background(), reference, "p226", "p225", "line-p225.wav")
+	require.NoError(t, err)
+	assert.Equal(t, "p226", argValue(runner.calls()[0].Args, argReferenceSpeakerIdx))
+}
+
+func TestRevoice_Invalid(t *testing.T) {
+	reference := writeTargets(t, "line.wav")[0]
+
+	xtts, runner := newTestTTS(t)
+	_, err := xtts.Revoice(context.Background(), reference, "p225", "out.wav")
+	assert.ErrorIs(t, err, ErrNotSupported, "Voice cloning models should use a speaker sample instead")
+
+	vits, vitsRunner := newTestTTS(t, WithModelId(tts.PresetVITSVCTK))
+	_, err = vits.Revoice(context.Background(), reference, "", "out.wav")
+	assert.Error(t, err)
+	_, err = vits.Revoice(context.Background(), "missing.wav", "p225", "out.wav")
+	assert.Error(t, err)
+
+	vits.ids.speakers[vits.modelName()] = []string{"p225"}
+	_, err = vits.Revoice(context.Background(), reference, "p999", "out.wav")
+	assert.ErrorIs(t, err, ErrUnknownSpeaker)
+	assert.Empty(t, runner.calls())
+	assert.Empty(t, vitsRunner.calls(), "Invalid requests should fail before running Coqui")
+}
+
+func TestRevoice_Backend(t *testing.T) {
+	reference := writeTargets(t, "line.wav")[0]
+	coqui, runner := newTestTTS(t, WithModelId(tts.PresetVITSVCTK), WithServer("http://localhost:5002"))
+
+	_, err := coqui.Revoice(context.Background(), reference, "p225", "line-p225.wav")
+	assert.ErrorIs(t, err, ErrNotSupported)
+	_, err = coqui.RevoiceFrom(context.Background(), reference, "p226", "p225", "line-p225.wav")
+	assert.ErrorIs(t, err, ErrNotSupported)
+	assert.Empty(t, runner.calls(), "The CLI should not run when a backend is configured")
+}
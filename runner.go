@@ -0,0 +1,69 @@
+package coqui
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+)
+
+// Command describes a single invocation of the Coqui TTS command line, or of an external encoder.
+type Command struct {
+	// Name is the executable to run.
+	Name string
+	// Args are the arguments passed to the executable.
+	Args []string
+	// Dir is the working directory of the command.
+	// If empty, the command runs in the current working directory.
+	Dir string
+	// Env holds extra environment variables in "KEY=value" form.
+	// They are added on top of the current process environment.
+	Env []string
+	// Stdin is passed to the command on standard input, e.g. audio for an external encoder.
+	Stdin []byte
+}
+
+// Output holds what a Command wrote while it was running.
+type Output struct {
+	// Stdout is everything the command wrote to standard output.
+	Stdout []byte
+	// Stderr is everything the command wrote to standard error.
+	Stderr []byte
+}
+
+// Combined returns stdout followed by stderr.
+// Useful for error messages where the stream a line came from doesn't matter.
+func (o Output) Combined() []byte {
+	return append(append([]byte{}, o.Stdout...), o.Stderr...)
+}
+
+// Runner executes Coqui TTS commands.
+// Every synthesis and listing call made by TTS goes through a Runner,
+// so tests can record the arguments and return fake output without a real Coqui install.
+type Runner interface {
+	Run(ctx context.Context, cmd Command) (Output, error)
+}
+
+// ExecRunner is the default Runner.
+// It spawns the command as a subprocess using os/exec.
+type ExecRunner struct{}
+
+// Run executes the command and waits for it to finish.
+// The output is returned even when the command fails, since it usually explains why.
+func (ExecRunner) Run(ctx context.Context, c Command) (Output, error) {
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.CommandContext(ctx, c.Name, c.Args...)
+	cmd.Dir = c.Dir
+	if len(c.Env) > 0 {
+		cmd.Env = append(os.Environ(), c.Env...)
+	}
+	if c.Stdin != nil {
+		cmd.Stdin = bytes.NewReader(c.Stdin)
+	}
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	return Output{Stdout: stdout.Bytes(), Stderr: stderr.Bytes()}, err
+}
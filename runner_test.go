@@ -0,0 +1,155 @@
+package coqui
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/pixellini/go-coqui/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRunner records every command it receives and answers with canned output.
+type fakeRunner struct {
+	mu       sync.Mutex
+	commands []Command
+	// respond builds the output for a command. When nil, a WAV file is written to --out_path.
+	respond func(cmd Command) (Output, error)
+}
+
+func (f *fakeRunner) Run(ctx context.Context, cmd Command) (Output, error) {
+	f.mu.Lock()
+	f.commands = append(f.commands, cmd)
+	f.mu.Unlock()
+
+	if f.respond != nil {
+		return f.respond(cmd)
+	}
+	return writeFakeWav(cmd)
+}
+
+// calls returns a copy of the recorded commands.
+func (f *fakeRunner) calls() []Command {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]Command{}, f.commands...)
+}
+
+// argValue returns the value following flag in args.
+func argValue(args []string, flag string) string {
+	for i, arg := range args {
+		if arg == flag && i+1 < len(args) {
+			return args[i+1]
+		}
+	}
+	return ""
+}
+
+// writeFakeWav writes a short silent WAV to the command's --out_path, like the real CLI would.
+func writeFakeWav(cmd Command) (Output, error) {
+	outPath := argValue(cmd.Args, argOutPath)
+	if outPath == "" {
+		return Output{}, errors.New("fake runner: missing --out_path")
+	}
+	if err := os.WriteFile(outPath, fakeWav(22050, 1, 2205), 0644); err != nil {
+		return Output{}, err
+	}
+	return Output{Stdout: []byte(" > Done.\n")}, nil
+}
+
+// fakeWav builds a 16-bit PCM WAV of silence.
+func fakeWav(sampleRate, channels, frames int) []byte {
+	dataSize := frames * channels * 2
+
+	var buf bytes.Buffer
+	buf.WriteString("RIFF")
+	binary.Write(&buf, binary.LittleEndian, uint32(36+dataSize))
+	buf.WriteString("WAVE")
+	buf.WriteString("fmt ")
+	binary.Write(&buf, binary.LittleEndian, uint32(16))
+	binary.Write(&buf, binary.LittleEndian, uint16(1))
+	binary.Write(&buf, binary.LittleEndian, uint16(channels))
+	binary.Write(&buf, binary.LittleEndian, uint32(sampleRate))
+	binary.Write(&buf, binary.LittleEndian, uint32(sampleRate*channels*2))
+	binary.Write(&buf, binary.LittleEndian, uint16(channels*2))
+	binary.Write(&buf, binary.LittleEndian, uint16(16))
+	buf.WriteString("data")
+	binary.Write(&buf, binary.LittleEndian, uint32(dataSize))
+	buf.Write(make([]byte, dataSize))
+	return buf.Bytes()
+}
+
+// newTestTTS creates a TTS instance backed by a fakeRunner writing to a temporary directory.
+func newTestTTS(t *testing.T, options ...Option) (*TTS, *fakeRunner) {
+	t.Helper()
+	runner := &fakeRunner{}
+	opts := append([]Option{
+		WithRunner(runner),
+		WithDevice(model.DeviceCPU),
+		WithOutputDir(t.TempDir() + string(filepath.Separator)),
+		// Retry immediately so tests don't wait on backoff.
+		WithRetryPolicy(RetryPolicy{}),
+	}, options...)
+
+	coqui, err := New(opts...)
+	require.NoError(t, err)
+	return coqui, runner
+}
+
+func TestRunner_SynthesizeUsesRunner(t *testing.T) {
+	coqui, runner := newTestTTS(t, WithSpeakerSample(writeTargets(t, "speaker.wav")[0]))
+
+	_, err := coqui.Synthesize("Hello world", "hello.wav")
+	require.NoError(t, err)
+
+	calls := runner.calls()
+	require.Len(t, calls, 1)
+	assert.Equal(t, defaultExecutable, calls[0].Name)
+
+	expected := append(toArgs(*coqui),
+		argText, "Hello world",
+		argOutPath, coqui.CurrentOutputDir()+"hello.wav",
+	)
+	assert.Equal(t, expected, calls[0].Args, "The runner should receive the arguments built by toArgs")
+	assert.FileExists(t, filepath.Join(coqui.CurrentOutputDir(), "hello.wav"))
+}
+
+func TestRunner_RetriesOnFailure(t *testing.T) {
+	coqui, runner := newTestTTS(t)
+	runner.respond = func(cmd Command) (Output, error) {
+		return Output{Stderr: []byte("boom")}, errors.New("exit status 1")
+	}
+
+	_, err := coqui.Synthesize("Hello world", "hello.wav")
+	require.Error(t, err)
+	assert.Len(t, runner.calls(), defaultMaxRetries, "Each attempt should go through the runner")
+}
+
+func TestRunner_StyleNotSupported(t *testing.T) {
+	coqui, runner := newTestTTS(t, WithTemperature(0.7))
+
+	_, err := coqui.Synthesize("Hello world", "hello.wav")
+	assert.ErrorIs(t, err, ErrNotSupported)
+	assert.Empty(t, runner.calls(), "The command can't take a temperature, so it shouldn't be run")
+}
+
+func TestExecRunner_SeparatesStreams(t *testing.T) {
+	if _, err := os.Stat("/bin/sh"); err != nil {
+		t.Skip("requires /bin/sh")
+	}
+
+	out, err := ExecRunner{}.Run(context.Background(), Command{
+		Name: "/bin/sh",
+		Args: []string{"-c", "echo out; echo err 1>&2"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "out\n", string(out.Stdout))
+	assert.Equal(t, "err\n", string(out.Stderr))
+	assert.Equal(t, "out\nerr\n", string(out.Combined()))
+}
@@ -0,0 +1,175 @@
+package coqui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pixellini/go-coqui/audio"
+)
+
+// SampleThresholds are the limits a speaker sample is checked against.
+// A zero field skips its check.
+type SampleThresholds struct {
+	// MinDuration is the shortest acceptable sample.
+	MinDuration time.Duration
+	// MaxDuration is the longest acceptable sample.
+	MaxDuration time.Duration
+	// MinSampleRate is the lowest acceptable sample rate.
+	MinSampleRate int
+	// MaxChannels is the most channels a sample may have.
+	MaxChannels int
+	// MaxClippingPercent is the highest acceptable percentage of clipped samples.
+	MaxClippingPercent float64
+	// MinSNR is the lowest acceptable estimated signal-to-noise ratio in dB.
+	MinSNR float64
+	// MaxSilenceRatio is the highest acceptable share of silence, from 0 to 1.
+	MaxSilenceRatio float64
+}
+
+// Validate checks that the thresholds are usable.
+func (th SampleThresholds) Validate() error {
+	if th.MinDuration < 0 || th.MaxDuration < 0 {
+		return fmt.Errorf("durations cannot be negative")
+	}
+	if th.MaxDuration > 0 && th.MaxDuration < th.MinDuration {
+		return fmt.Errorf("max duration %s is shorter than min duration %s", th.MaxDuration, th.MinDuration)
+	}
+	if th.MinSampleRate < 0 || th.MaxChannels < 0 || th.MinSNR < 0 {
+		return fmt.Errorf("sample rate, channels and SNR cannot be negative")
+	}
+	if th.MaxClippingPercent < 0 || th.MaxClippingPercent > 100 {
+		return fmt.Errorf("clipping percentage must be between 0 and 100, got %v", th.MaxClippingPercent)
+	}
+	if th.MaxSilenceRatio < 0 || th.MaxSilenceRatio > 1 {
+		return fmt.Errorf("silence ratio must be between 0 and 1, got %v", th.MaxSilenceRatio)
+	}
+	return nil
+}
+
+// sampleIssue is a threshold a sample failed.
+type sampleIssue struct {
+	// check names the measurement, so a warning isn't repeated for a check that already failed.
+	check   string
+	message string
+}
+
+// issues returns the thresholds the analysis fails.
+func (th SampleThresholds) issues(a audio.Analysis) []sampleIssue {
+	var issues []sampleIssue
+	add := func(check, format string, args ...any) {
+		issues = append(issues, sampleIssue{check: check, message: fmt.Sprintf(format, args...)})
+	}
+
+	if th.MinDuration > 0 && a.Duration < th.MinDuration {
+		add("duration", "duration %s is shorter than %s", a.Duration.Round(time.Millisecond), th.MinDuration)
+	}
+	if th.MaxDuration > 0 && a.Duration > th.MaxDuration {
+		add("duration", "duration %s is longer than %s", a.Duration.Round(time.Millisecond), th.MaxDuration)
+	}
+	if th.MinSampleRate > 0 && a.SampleRate < th.MinSampleRate {
+		add("sample rate", "sample rate %dHz is below %dHz", a.SampleRate, th.MinSampleRate)
+	}
+	if th.MaxChannels > 0 && a.Channels > th.MaxChannels {
+		add("channels", "%d channels is more than %d", a.Channels, th.MaxChannels)
+	}
+	if th.MaxClippingPercent > 0 && a.ClippingPercent > th.MaxClippingPercent {
+		add("clipping", "%.2f%% of samples are clipped, more than %.2f%%", a.ClippingPercent, th.MaxClippingPercent)
+	}
+	if th.MinSNR > 0 && a.SNR < th.MinSNR {
+		add("snr", "estimated SNR %.1fdB is below %.1fdB", a.SNR, th.MinSNR)
+	}
+	if th.MaxSilenceRatio > 0 && a.SilenceRatio > th.MaxSilenceRatio {
+		add("silence", "%.0f%% of the sample is silence, more than %.0f%%", a.SilenceRatio*100, th.MaxSilenceRatio*100)
+	}
+	return issues
+}
+
+// SamplePolicy controls how speaker samples are judged by ValidateSpeakerSample.
+// Samples that fail the Warn thresholds are reported with warnings;
+// samples that fail the Reject thresholds are unlikely to clone well and are reported with errors.
+type SamplePolicy struct {
+	// Warn are the thresholds a good sample meets.
+	Warn SampleThresholds
+	// Reject are the thresholds every usable sample meets.
+	Reject SampleThresholds
+}
+
+// DefaultSamplePolicy returns the policy used when none is configured.
+// It warns about samples outside the recommended 1-3 minutes of clean mono speech at 22.05kHz or more,
+// and rejects samples that are too short, noisy, clipped or quiet to clone.
+func DefaultSamplePolicy() SamplePolicy {
+	return SamplePolicy{
+		Warn: SampleThresholds{
+			MinDuration:        time.Minute,
+			MaxDuration:        3 * time.Minute,
+			MinSampleRate:      22050,
+			MaxChannels:        1,
+			MaxClippingPercent: 0.1,
+			MinSNR:             30,
+			MaxSilenceRatio:    0.3,
+		},
+		Reject: SampleThresholds{
+			MinDuration:        3 * time.Second,
+			MinSampleRate:      16000,
+			MaxClippingPercent: 1,
+			MinSNR:             15,
+			MaxSilenceRatio:    0.8,
+		},
+	}
+}
+
+// Validate checks that the policy's thresholds are usable.
+func (p SamplePolicy) Validate() error {
+	if err := p.Warn.Validate(); err != nil {
+		return fmt.Errorf("warn thresholds: %w", err)
+	}
+	if err := p.Reject.Validate(); err != nil {
+		return fmt.Errorf("reject thresholds: %w", err)
+	}
+	return nil
+}
+
+// SampleReport describes the quality of a speaker sample.
+type SampleReport struct {
+	// Path is the sample that was analysed.
+	Path string
+	// Analysis holds the measurements of the sample.
+	Analysis audio.Analysis
+	// Warnings describe where the sample falls short of the Warn thresholds.
+	Warnings []string
+	// Errors describe where the sample fails the Reject thresholds.
+	Errors []string
+}
+
+// OK reports whether the sample passed every Reject threshold.
+func (r SampleReport) OK() bool {
+	return len(r.Errors) == 0
+}
+
+// ValidateSpeakerSample analyses the WAV file at path and checks it against the sample policy,
+// so a poor sample can be caught before a slow synthesis produces a bad clone.
+// If the sample fails a Reject threshold, the report is returned with an error matching ErrSpeakerSampleRejected.
+func (t TTS) ValidateSpeakerSample(path string) (*SampleReport, error) {
+	clip, err := audio.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("invalid speaker sample %s: %w", path, err)
+	}
+
+	report := &SampleReport{Path: path, Analysis: audio.Analyze(clip)}
+	rejected := map[string]bool{}
+	for _, issue := range t.samplePolicy.Reject.issues(report.Analysis) {
+		rejected[issue.check] = true
+		report.Errors = append(report.Errors, issue.message)
+	}
+	for _, issue := range t.samplePolicy.Warn.issues(report.Analysis) {
+		if !rejected[issue.check] {
+			report.Warnings = append(report.Warnings, issue.message)
+		}
+	}
+
+	if !report.OK() {
+		return report, fmt.Errorf("%w: %s: %s", ErrSpeakerSampleRejected, path, strings.Join(report.Errors, "; "))
+	}
+	return report, nil
+}
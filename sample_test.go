@@ -0,0 +1,145 @@
+package coqui
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pixellini/go-coqui/audio"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeSample writes a speech-like WAV: a tone at amplitude for the first 80%, then quiet background noise.
+// Amplitudes above 1 are clipped.
+func writeSample(t *testing.T, sampleRate, channels int, amplitude float64, d time.Duration) string {
+	t.Helper()
+	frames := int(d.Seconds() * float64(sampleRate))
+	clip := &audio.Clip{SampleRate: sampleRate, Channels: channels, Format: audio.PCM16}
+	for i := 0; i < frames; i++ {
+		s := 0.001 * math.Sin(2*math.Pi*3000*float64(i)/float64(sampleRate))
+		if i < frames*8/10 {
+			s = max(-1, min(1, amplitude*math.Sin(2*math.Pi*220*float64(i)/float64(sampleRate))))
+		}
+		for c := 0; c < channels; c++ {
+			clip.Samples = append(clip.Samples, float32(s))
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "sample.wav")
+	require.NoError(t, clip.WriteFile(path))
+	return path
+}
+
+func TestValidateSpeakerSample(t *testing.T) {
+	tests := []struct {
+		name         string
+		path         string
+		wantWarnings []string
+		wantErrors   []string
+	}{
+		{
+			name: "good sample",
+			path: writeSample(t, 22050, 1, 0.5, 65*time.Second),
+		},
+		{
+			name:         "short stereo sample",
+			path:         writeSample(t, 24000, 2, 0.5, 10*time.Second),
+			wantWarnings: []string{"duration 10s is shorter than 1m0s", "2 channels is more than 1"},
+		},
+		{
+			name:       "too short to clone",
+			path:       writeSample(t, 24000, 1, 0.5, 2*time.Second),
+			wantErrors: []string{"duration 2s is shorter than 3s"},
+		},
+		{
+			name:         "telephone quality",
+			path:         writeSample(t, 8000, 1, 0.5, 5*time.Second),
+			wantWarnings: []string{"duration 5s is shorter than 1m0s"},
+			wantErrors:   []string{"sample rate 8000Hz is below 16000Hz"},
+		},
+		{
+			name:         "clipped",
+			path:         writeSample(t, 24000, 1, 2, 5*time.Second),
+			wantWarnings: []string{"duration 5s is shorter than 1m0s"},
+			wantErrors:   []string{"53.47% of samples are clipped, more than 1.00%"},
+		},
+	}
+
+	coqui, _ := newTestTTS(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report, err := coqui.ValidateSpeakerSample(tt.path)
+			require.NotNil(t, report)
+			assert.Equal(t, tt.path, report.Path)
+			assert.Equal(t, tt.wantWarnings, report.Warnings)
+			assert.Equal(t, tt.wantErrors, report.Errors)
+
+			if tt.wantErrors != nil {
+				assert.ErrorIs(t, err, ErrSpeakerSampleRejected)
+				assert.False(t, report.OK())
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, report.OK())
+		})
+	}
+}
+
+func TestValidateSpeakerSample_Analysis(t *testing.T) {
+	coqui, _ := newTestTTS(t)
+	report, err := coqui.ValidateSpeakerSample(writeSample(t, 24000, 1, 0.5, 5*time.Second))
+	require.NoError(t, err)
+
+	assert.Equal(t, 5*time.Second, report.Analysis.Duration)
+	assert.Equal(t, 24000, report.Analysis.SampleRate)
+	assert.Equal(t, 1, report.Analysis.Channels)
+	assert.Zero(t, report.Analysis.ClippingPercent)
+	assert.InDelta(t, 54, report.Analysis.SNR, 0.5)
+	assert.InDelta(t, 0.2, report.Analysis.SilenceRatio, 0.01)
+}
+
+func TestValidateSpeakerSample_CustomPolicy(t *testing.T) {
+	coqui, _ := newTestTTS(t, WithSamplePolicy(SamplePolicy{
+		Warn:   SampleThresholds{MaxSilenceRatio: 0.1},
+		Reject: SampleThresholds{MinSNR: 60},
+	}))
+
+	report, err := coqui.ValidateSpeakerSample(writeSample(t, 24000, 1, 0.5, time.Second))
+	assert.ErrorIs(t, err, ErrSpeakerSampleRejected)
+	assert.Equal(t, []string{"20% of the sample is silence, more than 10%"}, report.Warnings)
+	require.Len(t, report.Errors, 1)
+	assert.Contains(t, report.Errors[0], "is below 60.0dB")
+}
+
+func TestValidateSpeakerSample_Unreadable(t *testing.T) {
+	coqui, _ := newTestTTS(t)
+	notWav := filepath.Join(t.TempDir(), "notes.txt")
+	require.NoError(t, os.WriteFile(notWav, []byte("not audio"), 0644))
+
+	for _, path := range []string{notWav, "missing.wav"} {
+		report, err := coqui.ValidateSpeakerSample(path)
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, ErrSpeakerSampleRejected)
+		assert.Nil(t, report)
+	}
+}
+
+func TestSamplePolicy_Validate(t *testing.T) {
+	assert.NoError(t, DefaultSamplePolicy().Validate())
+	assert.NoError(t, SamplePolicy{}.Validate(), "the zero policy skips every check")
+
+	invalid := []SampleThresholds{
+		{MinDuration: -time.Second},
+		{MinDuration: time.Minute, MaxDuration: time.Second},
+		{MinSampleRate: -1},
+		{MaxClippingPercent: 101},
+		{MaxSilenceRatio: 1.5},
+	}
+	for _, th := range invalid {
+		assert.Error(t, SamplePolicy{Warn: th}.Validate(), "%+v", th)
+		assert.Error(t, WithSamplePolicy(SamplePolicy{Reject: th}).apply(&TTS{}), "%+v", th)
+	}
+}
@@ -0,0 +1,110 @@
+package coqui
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pixellini/go-coqui/audio"
+)
+
+// maxErrorBodySize limits how much of an error response is kept.
+const maxErrorBodySize = 64 << 10
+
+// serverBackend synthesizes audio using a running Coqui tts-server.
+// The server synthesizes with the model and vocoder it was started with, so the configured ones aren't sent.
+type serverBackend struct {
+	baseURL *url.URL
+}
+
+// newServerBackend creates a backend for the tts-server at baseURL.
+func newServerBackend(baseURL string) (*serverBackend, error) {
+	if baseURL == "" {
+		return nil, fmt.Errorf("server URL cannot be empty")
+	}
+
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid server URL %q: %w", baseURL, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid server URL %q: scheme must be http or https", baseURL)
+	}
+
+	return &serverBackend{baseURL: u}, nil
+}
+
+// synthesize posts the text to the server's /api/tts endpoint and returns the WAV audio.
+// Speaker samples are local files the server can't read, so they aren't supported.
+func (s *serverBackend) synthesize(ctx context.Context, t TTS, text string) ([]byte, attemptStats, error) {
+	if len(t.speakerSamples) > 0 {
+		return nil, attemptStats{}, fmt.Errorf("%w: speaker samples with the tts-server backend", ErrNotSupported)
+	}
+	if t.styled() {
+		return nil, attemptStats{}, fmt.Errorf("%w: temperature and speed with the tts-server backend", ErrNotSupported)
+	}
+
+	form := url.Values{}
+	form.Set("text", text)
+	if t.speakerIdx != "" {
+		form.Set("speaker_id", t.speakerIdx)
+	}
+	if t.model.IsMultilingual() {
+		form.Set("language_id", t.model.CurrentLanguage.String())
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.endpoint("/api/tts"), strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, attemptStats{}, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	start := time.Now()
+	client := t.httpClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, attemptStats{}, ctx.Err()
+		}
+		return nil, attemptStats{}, fmt.Errorf("%w: %w", ErrServerUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+		return nil, attemptStats{}, newServerError(resp.StatusCode, body)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, attemptStats{}, fmt.Errorf("%w: failed to read response: %w", ErrServerUnavailable, err)
+	}
+	wav, err := extractWav(body)
+	if err != nil {
+		return nil, attemptStats{}, fmt.Errorf("tts-server returned no audio: %w", err)
+	}
+
+	// The server doesn't report its own timings, so the request time stands in for the processing time.
+	stats := attemptStats{processingTime: time.Since(start)}
+	if clip, err := audio.DecodeBytes(wav); err == nil && clip.Duration() > 0 {
+		stats.realTimeFactor = stats.processingTime.Seconds() / clip.Duration().Seconds()
+	}
+	return wav, stats, nil
+}
+
+// close is a no-op; the server isn't owned by the TTS instance.
+func (s *serverBackend) close() error {
+	return nil
+}
+
+// endpoint returns the URL of path on the server.
+func (s *serverBackend) endpoint(path string) string {
+	return s.baseURL.JoinPath(path).String()
+}
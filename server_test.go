@@ -0,0 +1,157 @@
+package coqui
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pixellini/go-coqui/models/tts"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestServer starts a stand-in for Coqui's tts-server that records the last /api/tts form.
+func newTestServer(t *testing.T, handler http.HandlerFunc) (*httptest.Server, *url.Values) {
+	t.Helper()
+	var form url.Values
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/tts", func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		form = r.PostForm
+		handler(w, r)
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server, &form
+}
+
+func TestServer_Synthesize(t *testing.T) {
+	server, form := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "audio/wav")
+		w.Write(fakeWav(22050, 1, 22050))
+	})
+
+	coqui, runner := newTestTTS(t,
+		WithServer(server.URL),
+		WithModelId(tts.PresetVITSVCTK),
+		WithSpeakerIndex("p225"),
+	)
+
+	result, err := coqui.Synthesize("Hello world", "hello.wav")
+	require.NoError(t, err)
+	assert.Equal(t, 22050, result.SampleRate)
+	assert.Positive(t, result.ProcessingTime)
+	assert.Empty(t, runner.calls(), "The CLI should not be run when a server is configured")
+	assert.Empty(t, result.Model, "The server doesn't use the configured model, so it shouldn't be reported")
+	assert.Empty(t, result.Device)
+
+	assert.Equal(t, "Hello world", form.Get("text"))
+	assert.Equal(t, "p225", form.Get("speaker_id"))
+	assert.Empty(t, form.Get("language_id"), "Single language models shouldn't send a language")
+}
+
+func TestServer_SynthesizeBytesMultilingual(t *testing.T) {
+	wav := fakeWav(24000, 1, 100)
+	server, form := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write(wav)
+	})
+	coqui, _ := newTestTTS(t, WithServer(server.URL))
+
+	audio, err := coqui.SynthesizeBytes(context.Background(), "Bonjour")
+	require.NoError(t, err)
+	assert.Equal(t, wav, audio)
+	assert.Equal(t, "en", form.Get("language_id"))
+}
+
+func TestServer_ErrorStatus(t *testing.T) {
+	var requests atomic.Int32
+	server, _ := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		http.Error(w, "ValueError:  [!] Looks like you are using a multi-speaker model. You need to define either a `speaker_idx`", http.StatusInternalServerError)
+	})
+	coqui, _ := newTestTTS(t, WithServer(server.URL))
+
+	_, err := coqui.Synthesize("Hello", "hello.wav")
+	assert.ErrorIs(t, err, ErrSpeakerRequired)
+
+	var serverErr *ServerError
+	require.ErrorAs(t, err, &serverErr)
+	assert.Equal(t, http.StatusInternalServerError, serverErr.StatusCode)
+	assert.Equal(t, int32(1), requests.Load(), "Permanent failures should not be retried")
+}
+
+func TestServer_UnavailableIsRetried(t *testing.T) {
+	var requests atomic.Int32
+	server, _ := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write(fakeWav(22050, 1, 100))
+	})
+	coqui, _ := newTestTTS(t, WithServer(server.URL))
+
+	result, err := coqui.Synthesize("Hello", "hello.wav")
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Attempts)
+}
+
+func TestServer_Unreachable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	coqui, _ := newTestTTS(t, WithServer(server.URL), WithMaxRetries(1))
+	_, err := coqui.Synthesize("Hello", "hello.wav")
+	assert.ErrorIs(t, err, ErrServerUnavailable)
+	assert.True(t, IsTransient(err))
+}
+
+func TestServer_SpeakerSampleNotSupported(t *testing.T) {
+	server, _ := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {})
+	coqui, _ := newTestTTS(t, WithServer(server.URL), WithSpeakerSample(writeTargets(t, "speaker.wav")[0]))
+
+	_, err := coqui.Synthesize("Hello", "hello.wav")
+	assert.ErrorIs(t, err, ErrNotSupported)
+}
+
+func TestServer_StyleNotSupported(t *testing.T) {
+	server, _ := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {})
+	coqui, _ := newTestTTS(t, WithServer(server.URL), WithSpeed(1.2))
+
+	_, err := coqui.Synthesize("Hello", "hello.wav")
+	assert.ErrorIs(t, err, ErrNotSupported)
+}
+
+func TestServer_HTTPClientTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server, _ := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	})
+	defer close(release)
+
+	coqui, _ := newTestTTS(t,
+		WithServer(server.URL),
+		WithHTTPClient(&http.Client{Timeout: 50 * time.Millisecond}),
+		WithMaxRetries(1),
+	)
+	_, err := coqui.Synthesize("Hello", "hello.wav")
+	assert.ErrorIs(t, err, ErrServerUnavailable, "A hung server should time out")
+
+	assert.Error(t, WithHTTPClient(nil).apply(&TTS{}))
+}
+
+func TestWithServer_InvalidURL(t *testing.T) {
+	assert.Error(t, WithServer("").apply(&TTS{}))
+	assert.Error(t, WithServer("ftp://example.com").apply(&TTS{}))
+	assert.NoError(t, WithServer("http://localhost:5002").apply(&TTS{}))
+}
+
+func TestNew_ServerDoesNotNeedExecutable(t *testing.T) {
+	_, err := New(WithServer("http://localhost:5002"), WithExecutable("/does/not/exist"))
+	assert.NoError(t, err, "The CLI executable isn't needed when synthesizing with a server")
+}
@@ -0,0 +1,124 @@
+package coqui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// quotedID matches a single or double quoted ID in a printed Python dict or list.
+var quotedID = regexp.MustCompile(`'([^']*)'|"([^"]*)"`)
+
+// idCache holds the speakers and languages listed for each model, keyed by model name.
+// It's shared by pointer between copies of a TTS instance.
+type idCache struct {
+	mu        sync.Mutex
+	speakers  map[string][]string
+	languages map[string][]string
+}
+
+// ListSpeakers returns the speaker IDs of the configured multi-speaker model, e.g. "p225" for VCTK models,
+// for use with WithSpeakerIndex.
+// The list is read with "tts --list_speaker_idxs", which loads the model, the first time it's needed, then cached.
+// It isn't supported with WithWorker or WithServer.
+func (t TTS) ListSpeakers(ctx context.Context) ([]string, error) {
+	return t.listIDs(ctx, argListSpeakerIdxs, func(c *idCache) map[string][]string { return c.speakers })
+}
+
+// ListLanguages returns the language IDs of the configured multi-lingual model.
+// The list is read with "tts --list_language_idxs", which loads the model, the first time it's needed, then cached.
+// It isn't supported with WithWorker or WithServer.
+func (t TTS) ListLanguages(ctx context.Context) ([]string, error) {
+	return t.listIDs(ctx, argListLanguageIdxs, func(c *idCache) map[string][]string { return c.languages })
+}
+
+// listIDs runs a listing command for the configured model, caching the result.
+// The cache isn't locked while the command runs, since loading the model can take minutes.
+func (t TTS) listIDs(ctx context.Context, flag string, ids func(*idCache) map[string][]string) ([]string, error) {
+	name := t.modelName()
+	if t.ids != nil {
+		t.ids.mu.Lock()
+		list, ok := ids(t.ids)[name]
+		t.ids.mu.Unlock()
+		if ok {
+			return slices.Clone(list), nil
+		}
+	}
+	if err := t.requireCommand("listing speakers and languages"); err != nil {
+		return nil, err
+	}
+
+	out, err := t.runCommand(ctx, append(modelArgs(t), flag))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list IDs for %s: %w", name, newCommandError(out, err))
+	}
+	list, err := parseIDList(string(out.Stdout))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list IDs for %s: %w", name, err)
+	}
+
+	if t.ids != nil {
+		t.ids.mu.Lock()
+		ids(t.ids)[name] = list
+		t.ids.mu.Unlock()
+	}
+	return slices.Clone(list), nil
+}
+
+// cachedSpeakers returns the speakers listed for the configured model, if they have been listed.
+func (t TTS) cachedSpeakers() ([]string, bool) {
+	if t.ids == nil {
+		return nil, false
+	}
+	t.ids.mu.Lock()
+	defer t.ids.mu.Unlock()
+	list, ok := t.ids.speakers[t.modelName()]
+	return list, ok
+}
+
+// checkSpeaker verifies the speaker index against the speakers listed for the model.
+// Nothing is checked if the speakers haven't been listed, so it never runs the CLI.
+func (t TTS) checkSpeaker(idx string) error {
+	if idx == "" {
+		return nil
+	}
+	speakers, ok := t.cachedSpeakers()
+	if !ok || slices.Contains(speakers, idx) {
+		return nil
+	}
+	return fmt.Errorf("%w: %q is not one of the %d speakers of %s", ErrUnknownSpeaker, idx, len(speakers), t.modelName())
+}
+
+// parseIDList parses the speaker or language IDs printed by Coqui TTS.
+// They follow an "Available ... ids" line, as dict_keys(['p225', 'p226']) or {'p225': 0, 'p226': 1}.
+func parseIDList(output string) ([]string, error) {
+	lines := strings.Split(output, "\n")
+	start := 0
+	for i, line := range lines {
+		if strings.Contains(line, "Available") {
+			start = i + 1
+		}
+	}
+
+	for _, line := range lines[start:] {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "dict_keys(") && !strings.HasPrefix(line, "{") && !strings.HasPrefix(line, "[") {
+			continue
+		}
+
+		var ids []string
+		for _, m := range quotedID.FindAllStringSubmatch(line, -1) {
+			// A dict maps IDs to indexes, so only the quoted keys are IDs.
+			ids = append(ids, m[1]+m[2])
+		}
+		if len(ids) == 0 {
+			break
+		}
+		return ids, nil
+	}
+	return nil, errors.New("no IDs found, the model may not support multiple speakers or languages")
+}
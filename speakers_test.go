@@ -0,0 +1,135 @@
+package coqui
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pixellini/go-coqui/models/tts"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// vctkSpeakersOutput is the output of "tts --model_name tts_models/en/vctk/vits --list_speaker_idxs".
+const vctkSpeakersOutput = ` > tts_models/en/vctk/vits is already downloaded.
+ > Using model: vits
+ > Available speaker ids: (Set --speaker_idx flag to one of these values to use the multi-speaker model.
+dict_keys(['p225', 'p226', 'p227', 'p298'])
+`
+
+func TestParseIDList(t *testing.T) {
+	tests := []struct {
+		name     string
+		output   string
+		expected []string
+	}{
+		{name: "dict_keys", output: vctkSpeakersOutput, expected: []string{"p225", "p226", "p227", "p298"}},
+		{
+			name:     "Dict of indexes",
+			output:   " > Available language ids: (Set --language_idx flag to one of these values to use the multi-lingual model.\n{'en': 0, 'fr-fr': 1, 'pt-br': 2}\n",
+			expected: []string{"en", "fr-fr", "pt-br"},
+		},
+		{
+			name:     "Names with spaces and quotes",
+			output:   " > Available speaker ids:\ndict_keys(['Claribel Dervla', \"Ana O'Connor\"])\n",
+			expected: []string{"Claribel Dervla", "Ana O'Connor"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ids, err := parseIDList(tt.output)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, ids)
+		})
+	}
+
+	_, err := parseIDList(" > Using model: vits\n")
+	assert.Error(t, err)
+}
+
+func TestTTS_ListSpeakers(t *testing.T) {
+	coqui, runner := newTestTTS(t, WithModelId(tts.PresetVITSVCTK))
+	runner.respond = func(cmd Command) (Output, error) {
+		return Output{Stdout: []byte(vctkSpeakersOutput)}, nil
+	}
+
+	speakers, err := coqui.ListSpeakers(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"p225", "p226", "p227", "p298"}, speakers)
+
+	_, err = coqui.ListSpeakers(context.Background())
+	require.NoError(t, err)
+	calls := runner.calls()
+	require.Len(t, calls, 1, "Speakers should be cached per model")
+	assert.Equal(t, "tts_models/en/vctk/vits", argValue(calls[0].Args, argModelName))
+	assert.Contains(t, calls[0].Args, argListSpeakerIdxs)
+
+	assert.NoError(t, coqui.SetCurrentSpeakerIndex("p298"))
+	assert.ErrorIs(t, coqui.SetCurrentSpeakerIndex("p999"), ErrUnknownSpeaker)
+}
+
+func TestTTS_ListLanguages(t *testing.T) {
+	coqui, runner := newTestTTS(t)
+	runner.respond = func(cmd Command) (Output, error) {
+		return Output{Stdout: []byte(" > Available language ids:\ndict_keys(['en', 'es', 'fr'])\n")}, nil
+	}
+
+	languages, err := coqui.ListLanguages(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"en", "es", "fr"}, languages)
+	assert.Contains(t, runner.calls()[0].Args, argListLanguageIdxs)
+}
+
+func TestTTS_ListSpeakers_DoesNotBlockCache(t *testing.T) {
+	coqui, runner := newTestTTS(t, WithModelId(tts.PresetVITSVCTK))
+	started, release := make(chan struct{}), make(chan struct{})
+	runner.respond = func(cmd Command) (Output, error) {
+		close(started)
+		<-release
+		return Output{Stdout: []byte(vctkSpeakersOutput)}, nil
+	}
+
+	listed := make(chan error, 1)
+	go func() {
+		_, err := coqui.ListSpeakers(context.Background())
+		listed <- err
+	}()
+	<-started
+
+	checked := make(chan error, 1)
+	go func() { checked <- coqui.SetCurrentSpeakerIndex("p225") }()
+	select {
+	case err := <-checked:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("checking a speaker should not wait for a listing command to finish")
+	}
+
+	close(release)
+	require.NoError(t, <-listed)
+	assert.ErrorIs(t, coqui.SetCurrentSpeakerIndex("p999"), ErrUnknownSpeaker, "the listed speakers should be cached")
+}
+
+func TestTTS_ListSpeakers_Backend(t *testing.T) {
+	coqui, runner := newTestTTS(t, WithServer("http://localhost:5002"))
+
+	_, err := coqui.ListSpeakers(context.Background())
+	assert.ErrorIs(t, err, ErrNotSupported)
+	_, err = coqui.ListLanguages(context.Background())
+	assert.ErrorIs(t, err, ErrNotSupported)
+	assert.Empty(t, runner.calls(), "The CLI should not run when a backend is configured")
+}
+
+func TestSynthesize_UnknownSpeaker(t *testing.T) {
+	coqui, runner := newTestTTS(t, WithModelId(tts.PresetVITSVCTK), WithSpeakerIndex("p999"))
+	runner.respond = func(cmd Command) (Output, error) {
+		return Output{Stdout: []byte(vctkSpeakersOutput)}, nil
+	}
+	_, err := coqui.ListSpeakers(context.Background())
+	require.NoError(t, err)
+
+	_, err = coqui.Synthesize("Hello world", "hello.wav")
+	assert.ErrorIs(t, err, ErrUnknownSpeaker)
+	assert.Len(t, runner.calls(), 1, "Synthesis should not start with an unknown speaker")
+}
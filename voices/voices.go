@@ -0,0 +1,223 @@
+// Package voices manages a library of named voice profiles.
+// A profile bundles everything needed to synthesize in a voice: the speaker samples, the preferred model,
+// language and speaker index, and style settings such as temperature and speed.
+package voices
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/pixellini/go-coqui/model"
+)
+
+// ErrVoiceNotFound is returned when a library has no voice with the requested name.
+var ErrVoiceNotFound = errors.New("voice not found")
+
+// ProfileFile is the file in a voice directory that holds the profile settings.
+const ProfileFile = "voice.json"
+
+// Profile describes a named voice.
+// Every setting is optional; empty settings leave the synthesis configuration as it is.
+type Profile struct {
+	// Name identifies the voice in its library, e.g. "narrator-anna".
+	Name string `json:"-"`
+	// Samples are the speaker sample WAV files the voice is cloned from.
+	Samples []string `json:"samples,omitempty"`
+	// Model is the name of the preferred model as Coqui TTS lists it, e.g. "tts_models/multilingual/multi-dataset/xtts_v2".
+	Model string `json:"model,omitempty"`
+	// Language is the language the voice speaks.
+	Language model.Language `json:"language,omitempty"`
+	// SpeakerIndex is the speaker of a multi-speaker model, e.g. "p225".
+	SpeakerIndex string `json:"speaker_idx,omitempty"`
+	// Temperature controls how varied the voice sounds. 0 uses the model's default.
+	Temperature float64 `json:"temperature,omitempty"`
+	// Speed is the speaking rate, where 1 is normal speed. 0 uses the model's default.
+	Speed float64 `json:"speed,omitempty"`
+}
+
+// Validate checks that the profile settings are valid.
+// Samples aren't read; they're checked when the voice is used.
+func (p Profile) Validate() error {
+	if p.Name == "" {
+		return fmt.Errorf("voice name cannot be empty")
+	}
+	if p.Model != "" {
+		if _, err := model.ParseIdentifier(p.Model); err != nil {
+			return fmt.Errorf("voice %s: %w", p.Name, err)
+		}
+	}
+	if p.Language != "" && !p.Language.IsSupported() {
+		return fmt.Errorf("voice %s: unsupported language %q", p.Name, p.Language)
+	}
+	if p.Temperature < 0 {
+		return fmt.Errorf("voice %s: temperature cannot be negative", p.Name)
+	}
+	if p.Speed < 0 {
+		return fmt.Errorf("voice %s: speed cannot be negative", p.Name)
+	}
+	return nil
+}
+
+// Library is a set of voice profiles looked up by name.
+type Library struct {
+	profiles map[string]Profile
+}
+
+// New creates a library holding profiles.
+// Returns an error if a profile is invalid or two profiles share a name.
+func New(profiles ...Profile) (*Library, error) {
+	l := &Library{profiles: make(map[string]Profile, len(profiles))}
+	for _, p := range profiles {
+		if err := p.Validate(); err != nil {
+			return nil, err
+		}
+		if _, ok := l.profiles[p.Name]; ok {
+			return nil, fmt.Errorf("duplicate voice %s", p.Name)
+		}
+		p.Samples = slices.Clone(p.Samples)
+		l.profiles[p.Name] = p
+	}
+	return l, nil
+}
+
+// Load loads a library from path, which is either a voice directory (see LoadDir) or a JSON manifest (see LoadManifest).
+func Load(path string) (*Library, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load voices: %w", err)
+	}
+	if info.IsDir() {
+		return LoadDir(path)
+	}
+	return LoadManifest(path)
+}
+
+// LoadDir loads a library from a directory with a subdirectory for every voice, named after it.
+// The WAV files in a voice's directory are its samples, and an optional voice.json file holds the rest of its profile.
+// Samples listed in voice.json are used instead of the WAV files, relative to the voice's directory.
+func LoadDir(dir string) (*Library, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load voices: %w", err)
+	}
+
+	var profiles []Profile
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		p, err := loadVoiceDir(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		p.Name = entry.Name()
+		profiles = append(profiles, p)
+	}
+	return New(profiles...)
+}
+
+// loadVoiceDir reads the profile of the voice stored in dir.
+func loadVoiceDir(dir string) (Profile, error) {
+	var p Profile
+	if err := readJSON(filepath.Join(dir, ProfileFile), &p); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return Profile{}, err
+	}
+	if len(p.Samples) > 0 {
+		p.Samples = resolve(dir, p.Samples)
+		return p, nil
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return Profile{}, fmt.Errorf("failed to load voice: %w", err)
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.EqualFold(filepath.Ext(entry.Name()), ".wav") {
+			p.Samples = append(p.Samples, filepath.Join(dir, entry.Name()))
+		}
+	}
+	return p, nil
+}
+
+// LoadManifest loads a library from a JSON manifest mapping voice names to their profiles:
+//
+//	{
+//	  "narrator-anna": {
+//	    "samples": ["anna/intro.wav", "anna/chapter-1.wav"],
+//	    "model": "tts_models/multilingual/multi-dataset/xtts_v2",
+//	    "language": "en",
+//	    "speed": 1.1
+//	  }
+//	}
+//
+// Relative sample paths are resolved against the manifest's directory.
+func LoadManifest(path string) (*Library, error) {
+	var manifest map[string]Profile
+	if err := readJSON(path, &manifest); err != nil {
+		return nil, err
+	}
+
+	dir := filepath.Dir(path)
+	profiles := make([]Profile, 0, len(manifest))
+	for name, p := range manifest {
+		p.Name = name
+		p.Samples = resolve(dir, p.Samples)
+		profiles = append(profiles, p)
+	}
+	return New(profiles...)
+}
+
+// readJSON decodes the JSON file at path into v, rejecting unknown fields so typos don't go unnoticed.
+func readJSON(path string, v any) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to load voices: %w", err)
+	}
+	defer f.Close()
+
+	dec := json.NewDecoder(f)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return nil
+}
+
+// resolve makes relative paths relative to dir.
+func resolve(dir string, paths []string) []string {
+	resolved := make([]string, len(paths))
+	for i, path := range paths {
+		if filepath.IsAbs(path) {
+			resolved[i] = path
+		} else {
+			resolved[i] = filepath.Join(dir, path)
+		}
+	}
+	return resolved
+}
+
+// Get returns the profile of the named voice.
+// Returns an error matching ErrVoiceNotFound if the library has no such voice.
+func (l *Library) Get(name string) (Profile, error) {
+	p, ok := l.profiles[name]
+	if !ok {
+		return Profile{}, fmt.Errorf("%w: %s", ErrVoiceNotFound, name)
+	}
+	p.Samples = slices.Clone(p.Samples)
+	return p, nil
+}
+
+// Names returns the names of the voices in the library, sorted.
+func (l *Library) Names() []string {
+	names := make([]string, 0, len(l.profiles))
+	for name := range l.profiles {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
@@ -0,0 +1,147 @@
+package voices
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pixellini/go-coqui/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeFile writes content to path, creating its directory.
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name     string
+		profiles []Profile
+		wantErr  bool
+	}{
+		{name: "valid", profiles: []Profile{{Name: "anna", Model: "tts_models/en/vctk/vits", Language: model.English, Speed: 1.2}}},
+		{name: "empty library"},
+		{name: "no name", profiles: []Profile{{Model: "tts_models/en/vctk/vits"}}, wantErr: true},
+		{name: "duplicate name", profiles: []Profile{{Name: "anna"}, {Name: "anna"}}, wantErr: true},
+		{name: "bad model", profiles: []Profile{{Name: "anna", Model: "xtts"}}, wantErr: true},
+		{name: "bad language", profiles: []Profile{{Name: "anna", Language: "xx"}}, wantErr: true},
+		{name: "negative temperature", profiles: []Profile{{Name: "anna", Temperature: -1}}, wantErr: true},
+		{name: "negative speed", profiles: []Profile{{Name: "anna", Speed: -1}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lib, err := New(tt.profiles...)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, lib.Names(), len(tt.profiles))
+		})
+	}
+}
+
+func TestLibrary_Get(t *testing.T) {
+	lib, err := New(Profile{Name: "anna", Samples: []string{"a.wav"}}, Profile{Name: "ben"})
+	require.NoError(t, err)
+
+	p, err := lib.Get("anna")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a.wav"}, p.Samples)
+
+	p.Samples[0] = "changed.wav"
+	p, _ = lib.Get("anna")
+	assert.Equal(t, []string{"a.wav"}, p.Samples, "Get should return a copy of the samples")
+
+	_, err = lib.Get("carl")
+	assert.ErrorIs(t, err, ErrVoiceNotFound)
+
+	assert.Equal(t, []string{"anna", "ben"}, lib.Names())
+}
+
+func TestLoadDir(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "narrator-anna", "intro.wav"), "")
+	writeFile(t, filepath.Join(dir, "narrator-anna", "chapter-1.WAV"), "")
+	writeFile(t, filepath.Join(dir, "narrator-anna", "notes.txt"), "")
+	writeFile(t, filepath.Join(dir, "narrator-anna", ProfileFile), `{"language": "en", "temperature": 0.7, "speed": 1.1}`)
+	writeFile(t, filepath.Join(dir, "vctk", ProfileFile), `{"model": "tts_models/en/vctk/vits", "speaker_idx": "p225"}`)
+	writeFile(t, filepath.Join(dir, "picked", "one.wav"), "")
+	writeFile(t, filepath.Join(dir, "picked", "two.wav"), "")
+	writeFile(t, filepath.Join(dir, "picked", ProfileFile), `{"samples": ["two.wav"]}`)
+	writeFile(t, filepath.Join(dir, "README.md"), "")
+
+	lib, err := Load(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"narrator-anna", "picked", "vctk"}, lib.Names())
+
+	anna, err := lib.Get("narrator-anna")
+	require.NoError(t, err)
+	assert.Equal(t, Profile{
+		Name: "narrator-anna",
+		Samples: []string{
+			filepath.Join(dir, "narrator-anna", "chapter-1.WAV"),
+			filepath.Join(dir, "narrator-anna", "intro.wav"),
+		},
+		Language:    model.English,
+		Temperature: 0.7,
+		Speed:       1.1,
+	}, anna)
+
+	vctk, _ := lib.Get("vctk")
+	assert.Empty(t, vctk.Samples)
+	assert.Equal(t, "p225", vctk.SpeakerIndex)
+
+	picked, _ := lib.Get("picked")
+	assert.Equal(t, []string{filepath.Join(dir, "picked", "two.wav")}, picked.Samples, "samples listed in voice.json should be used instead of the WAV files")
+}
+
+func TestLoadDir_InvalidProfile(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "anna", ProfileFile), `{"sped": 1.1}`)
+
+	_, err := LoadDir(dir)
+	assert.Error(t, err, "unknown fields should be rejected")
+}
+
+func TestLoadManifest(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "voices.json")
+	writeFile(t, path, `{
+		"narrator-anna": {
+			"samples": ["anna/intro.wav", "/abs/anna.wav"],
+			"model": "tts_models/multilingual/multi-dataset/xtts_v2",
+			"language": "en",
+			"speed": 1.1
+		},
+		"vctk-p225": {"model": "tts_models/en/vctk/vits", "speaker_idx": "p225"}
+	}`)
+
+	lib, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"narrator-anna", "vctk-p225"}, lib.Names())
+
+	anna, err := lib.Get("narrator-anna")
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(dir, "anna", "intro.wav"), "/abs/anna.wav"}, anna.Samples)
+	assert.Equal(t, "tts_models/multilingual/multi-dataset/xtts_v2", anna.Model)
+	assert.Equal(t, 1.1, anna.Speed)
+}
+
+func TestLoad_Errors(t *testing.T) {
+	dir := t.TempDir()
+	invalid := filepath.Join(dir, "invalid.json")
+	writeFile(t, invalid, `{"anna": {"language": "xx"}}`)
+	malformed := filepath.Join(dir, "malformed.json")
+	writeFile(t, malformed, `{"anna":`)
+
+	for _, path := range []string{filepath.Join(dir, "missing.json"), invalid, malformed} {
+		_, err := Load(path)
+		assert.Error(t, err, path)
+	}
+}
@@ -0,0 +1,363 @@
+package coqui
+
+import (
+	"bufio"
+	"context"
+	_ "embed"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sync"
+	"time"
+)
+
+// workerScript is the Python worker bundled with go-coqui.
+// It is written to a temporary file when the worker starts.
+//
+//go:embed worker.py
+var workerScript []byte
+
+const (
+	// defaultPython is the interpreter used to run the worker when WithPython isn't set.
+	defaultPython = "python3"
+	// maxFrameSize guards against reading a corrupt frame length as a huge allocation.
+	maxFrameSize = 1 << 30
+	// stderrTailSize is how much of the worker's stderr is kept for error messages.
+	stderrTailSize = 64 << 10
+)
+
+// errWorkerFailed is the underlying error of a CommandError returned for a failed worker request.
+var errWorkerFailed = errors.New("worker synthesis failed")
+
+// workerRequest is the JSON frame sent to the worker for every synthesis.
+type workerRequest struct {
+	ID          uint64   `json:"id"`
+	Text        string   `json:"text"`
+	ModelName   string   `json:"model_name,omitempty"`
+	ModelPath   string   `json:"model_path,omitempty"`
+	VocoderName string   `json:"vocoder_name,omitempty"`
+	Device      string   `json:"device,omitempty"`
+	SpeakerWav  []string `json:"speaker_wav,omitempty"`
+	Speaker     string   `json:"speaker,omitempty"`
+	Language    string   `json:"language,omitempty"`
+	Temperature float64  `json:"temperature,omitempty"`
+	Speed       float64  `json:"speed,omitempty"`
+}
+
+// workerResponse is the JSON frame the worker sends back.
+// When OK is true it is followed by a binary frame holding the WAV audio.
+type workerResponse struct {
+	ID             uint64  `json:"id"`
+	OK             bool    `json:"ok"`
+	Error          string  `json:"error"`
+	ProcessingTime float64 `json:"processing_time"`
+	RealTimeFactor float64 `json:"real_time_factor"`
+}
+
+// worker is a long-lived synthesis process that keeps models loaded between calls.
+// It is started on first use and restarted if it dies or a request is cancelled.
+// Requests are serialised; the worker handles one synthesis at a time.
+type worker struct {
+	// command overrides the bundled script, e.g. with a fake worker in tests.
+	// If empty, the bundled script is run with the configured Python interpreter.
+	command []string
+
+	mu     sync.Mutex
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	stdout *bufio.Reader
+	stderr *tailBuffer
+	script string
+	nextID uint64
+}
+
+// newWorker creates a worker that is started on first use.
+func newWorker(command []string) *worker {
+	return &worker{command: command}
+}
+
+// synthesize sends a request to the worker and waits for the audio.
+// If ctx is done before the worker answers, the worker is stopped and restarted on the next call.
+func (w *worker) synthesize(ctx context.Context, t TTS, text string) ([]byte, attemptStats, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.cmd == nil {
+		if err := w.start(t); err != nil {
+			return nil, attemptStats{}, err
+		}
+	}
+
+	w.nextID++
+	req := t.workerRequest(w.nextID, text)
+
+	type reply struct {
+		resp  workerResponse
+		audio []byte
+		err   error
+	}
+	done := make(chan reply, 1)
+	go func() {
+		resp, audio, err := w.exchange(req)
+		done <- reply{resp, audio, err}
+	}()
+
+	var r reply
+	select {
+	case <-ctx.Done():
+		// The worker can't be interrupted mid-synthesis, so stop it to unblock the exchange.
+		w.stop()
+		<-done
+		return nil, attemptStats{}, ctx.Err()
+	case r = <-done:
+	}
+
+	if r.err != nil {
+		// The protocol is out of sync or the process died, either way it has to be restarted.
+		// Stderr is only fully copied once the process has been waited for, so stop it before reading.
+		w.stop()
+		stderr := w.stderr.Bytes()
+		return nil, attemptStats{}, newCommandError(Output{Stderr: stderr}, fmt.Errorf("worker: %w", r.err))
+	}
+	if !r.resp.OK {
+		return nil, attemptStats{}, newCommandError(Output{Stderr: []byte(r.resp.Error)}, errWorkerFailed)
+	}
+
+	return r.audio, attemptStats{
+		processingTime: time.Duration(r.resp.ProcessingTime * float64(time.Second)),
+		realTimeFactor: r.resp.RealTimeFactor,
+	}, nil
+}
+
+// exchange writes a request frame and reads the response frames.
+func (w *worker) exchange(req workerRequest) (workerResponse, []byte, error) {
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return workerResponse{}, nil, fmt.Errorf("failed to encode request: %w", err)
+	}
+	if err := writeFrame(w.stdin, payload); err != nil {
+		return workerResponse{}, nil, fmt.Errorf("failed to send request: %w", err)
+	}
+
+	frame, err := readFrame(w.stdout)
+	if err != nil {
+		return workerResponse{}, nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	var resp workerResponse
+	if err := json.Unmarshal(frame, &resp); err != nil {
+		return workerResponse{}, nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	if resp.ID != req.ID {
+		return workerResponse{}, nil, fmt.Errorf("response id %d does not match request id %d", resp.ID, req.ID)
+	}
+	if !resp.OK {
+		return resp, nil, nil
+	}
+
+	audio, err := readFrame(w.stdout)
+	if err != nil {
+		return workerResponse{}, nil, fmt.Errorf("failed to read audio: %w", err)
+	}
+	return resp, audio, nil
+}
+
+// start launches the worker process using the interpreter, working directory and environment of t.
+func (w *worker) start(t TTS) error {
+	name, args := "", []string(nil)
+	if len(w.command) > 0 {
+		name, args = w.command[0], w.command[1:]
+	} else {
+		script, err := w.writeScript()
+		if err != nil {
+			return err
+		}
+		name = t.python
+		if name == "" {
+			name = defaultPython
+		}
+		args = []string{"-u", script}
+	}
+
+	if _, err := exec.LookPath(name); err != nil {
+		return &ExecutableNotFoundError{Name: name, Err: err}
+	}
+
+	cmd := exec.Command(name, args...)
+	cmd.Dir = t.workingDir
+	if len(t.env) > 0 {
+		cmd.Env = append(os.Environ(), t.env...)
+	}
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return fmt.Errorf("failed to open worker stdin: %w", err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("failed to open worker stdout: %w", err)
+	}
+	stderr := &tailBuffer{limit: stderrTailSize}
+	cmd.Stderr = stderr
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start worker: %w", err)
+	}
+
+	w.cmd = cmd
+	w.stdin = stdin
+	w.stdout = bufio.NewReader(stdout)
+	w.stderr = stderr
+	return nil
+}
+
+// writeScript writes the bundled worker script to a temporary file, once.
+func (w *worker) writeScript() (string, error) {
+	if w.script != "" {
+		return w.script, nil
+	}
+
+	f, err := os.CreateTemp("", "go-coqui-worker-*.py")
+	if err != nil {
+		return "", fmt.Errorf("failed to create worker script: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(workerScript); err != nil {
+		os.Remove(f.Name())
+		return "", fmt.Errorf("failed to write worker script: %w", err)
+	}
+
+	w.script = f.Name()
+	return w.script, nil
+}
+
+// stop kills the worker process, if it's running.
+func (w *worker) stop() {
+	if w.cmd == nil {
+		return
+	}
+	w.stdin.Close()
+	w.cmd.Process.Kill()
+	w.cmd.Wait()
+	w.cmd = nil
+}
+
+// close shuts the worker down.
+// Closing stdin lets the worker exit on its own; it's killed if it doesn't exit promptly.
+func (w *worker) close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.script != "" {
+		defer func() {
+			os.Remove(w.script)
+			w.script = ""
+		}()
+	}
+	if w.cmd == nil {
+		return nil
+	}
+
+	w.stdin.Close()
+	exited := make(chan error, 1)
+	go func() { exited <- w.cmd.Wait() }()
+
+	var err error
+	select {
+	case err = <-exited:
+	case <-time.After(5 * time.Second):
+		w.cmd.Process.Kill()
+		err = <-exited
+	}
+	w.cmd = nil
+
+	if err != nil {
+		return fmt.Errorf("worker exited with error: %w", err)
+	}
+	return nil
+}
+
+// workerRequest builds the request for synthesizing text with the configuration of t.
+func (t TTS) workerRequest(id uint64, text string) workerRequest {
+	req := workerRequest{
+		ID:          id,
+		Text:        text,
+		Device:      t.resolveDevice().String(),
+		Speaker:     t.speakerIdx,
+		Language:    t.model.CurrentLanguage.String(),
+		Temperature: t.temperature,
+		Speed:       t.speed,
+	}
+	if t.modelPath != "" {
+		req.ModelPath = t.modelPath
+	} else {
+		req.ModelName = t.Name()
+	}
+	if t.vocoder.IsValid() {
+		req.VocoderName = t.VocoderName()
+	}
+	if len(t.speakerSamples) > 0 {
+		req.SpeakerWav = t.speakerSamples
+	}
+	return req
+}
+
+// writeFrame writes a length-prefixed frame.
+func writeFrame(w io.Writer, payload []byte) error {
+	var header [4]byte
+	binary.BigEndian.PutUint32(header[:], uint32(len(payload)))
+	if _, err := w.Write(header[:]); err != nil {
+		return err
+	}
+	_, err := w.Write(payload)
+	return err
+}
+
+// readFrame reads a length-prefixed frame.
+func readFrame(r io.Reader) ([]byte, error) {
+	var header [4]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return nil, err
+	}
+	size := binary.BigEndian.Uint32(header[:])
+	if size > maxFrameSize {
+		return nil, fmt.Errorf("frame of %d bytes exceeds the maximum size", size)
+	}
+
+	payload := make([]byte, size)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+// tailBuffer keeps the last limit bytes written to it.
+// Used to hold on to the end of the worker's stderr for error messages without growing forever.
+type tailBuffer struct {
+	mu    sync.Mutex
+	limit int
+	buf   []byte
+}
+
+// Write appends p, discarding the oldest bytes beyond the limit.
+func (b *tailBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.buf = append(b.buf, p...)
+	if over := len(b.buf) - b.limit; over > 0 {
+		b.buf = append(b.buf[:0], b.buf[over:]...)
+	}
+	return len(p), nil
+}
+
+// Bytes returns a copy of the buffered bytes.
+func (b *tailBuffer) Bytes() []byte {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]byte{}, b.buf...)
+}
@@ -0,0 +1,125 @@
+"""Persistent synthesis worker for go-coqui.
+
+Loads Coqui TTS models once and keeps them in memory between requests.
+
+Protocol (stdin/stdout), every frame is a 4 byte big-endian length followed by the payload:
+  request:  one JSON frame
+  response: one JSON frame, followed by one binary frame with the WAV audio when "ok" is true
+
+Anything Coqui prints is redirected to stderr so stdout only carries frames.
+"""
+
+import io
+import json
+import os
+import struct
+import sys
+import time
+import traceback
+
+
+def read_frame(stream):
+    header = stream.read(4)
+    if len(header) < 4:
+        return None
+    (size,) = struct.unpack(">I", header)
+    payload = stream.read(size)
+    if len(payload) < size:
+        return None
+    return payload
+
+
+def write_frame(stream, payload):
+    stream.write(struct.pack(">I", len(payload)))
+    stream.write(payload)
+    stream.flush()
+
+
+def write_json(stream, value):
+    write_frame(stream, json.dumps(value).encode("utf-8"))
+
+
+def model_key(req):
+    return (req.get("model_name"), req.get("model_path"), req.get("vocoder_name"), req.get("device"))
+
+
+def load_model(models, req):
+    key = model_key(req)
+    if key in models:
+        return models[key]
+
+    from TTS.api import TTS
+
+    kwargs = {"progress_bar": False}
+    if req.get("model_path"):
+        kwargs["model_path"] = req["model_path"]
+    else:
+        kwargs["model_name"] = req["model_name"]
+    if req.get("vocoder_name"):
+        kwargs["vocoder_name"] = req["vocoder_name"]
+
+    tts = TTS(**kwargs)
+    if req.get("device"):
+        tts = tts.to(req["device"])
+
+    models[key] = tts
+    return tts
+
+
+def synthesize(tts, req):
+    kwargs = {}
+    if req.get("speaker_wav"):
+        kwargs["speaker_wav"] = req["speaker_wav"]
+    if req.get("speaker") and tts.is_multi_speaker:
+        kwargs["speaker"] = req["speaker"]
+    if req.get("language") and tts.is_multi_lingual:
+        kwargs["language"] = req["language"]
+    if req.get("speed"):
+        kwargs["speed"] = req["speed"]
+    if req.get("temperature"):
+        kwargs["temperature"] = req["temperature"]
+
+    start = time.time()
+    wav = tts.tts(text=req["text"], **kwargs)
+    processing_time = time.time() - start
+
+    buf = io.BytesIO()
+    tts.synthesizer.save_wav(wav, buf)
+
+    audio_time = len(wav) / tts.synthesizer.output_sample_rate
+    rtf = processing_time / audio_time if audio_time else 0
+    return buf.getvalue(), processing_time, rtf
+
+
+def main():
+    # Keep the protocol on the original stdout, and send everything else to stderr.
+    proto_out = os.fdopen(os.dup(1), "wb")
+    os.dup2(2, 1)
+    sys.stdout = sys.stderr
+    proto_in = sys.stdin.buffer
+
+    models = {}
+    while True:
+        frame = read_frame(proto_in)
+        if frame is None:
+            return
+
+        req = json.loads(frame)
+        try:
+            tts = load_model(models, req)
+            audio, processing_time, rtf = synthesize(tts, req)
+        except Exception:
+            write_json(proto_out, {"id": req.get("id"), "ok": False, "error": traceback.format_exc()})
+            continue
+
+        write_json(proto_out, {
+            "id": req.get("id"),
+            "ok": True,
+            "processing_time": processing_time,
+            "real_time_factor": rtf,
+        })
+        write_frame(proto_out, audio)
+
+
+if __name__ == "__main__":
+    main()
@@ -0,0 +1,207 @@
+package coqui
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const fakeWorkerEnv = "GO_COQUI_FAKE_WORKER=1"
+
+// TestWorkerHelperProcess isn't a real test, it's the fake worker started by the tests below.
+// It speaks the worker protocol, reporting how many requests it has handled as the real-time factor
+// so tests can tell whether the same process served every call.
+func TestWorkerHelperProcess(t *testing.T) {
+	if os.Getenv("GO_COQUI_FAKE_WORKER") != "1" {
+		return
+	}
+
+	in := bufio.NewReader(os.Stdin)
+	handled := 0
+	for {
+		frame, err := readFrame(in)
+		if err != nil {
+			os.Exit(0)
+		}
+		var req workerRequest
+		if err := json.Unmarshal(frame, &req); err != nil {
+			os.Exit(2)
+		}
+		handled++
+
+		switch req.Text {
+		case "fail":
+			resp, _ := json.Marshal(workerResponse{ID: req.ID, Error: "ValueError: Model `" + req.ModelName + "` not found in the model list."})
+			writeFrame(os.Stdout, resp)
+		case "crash":
+			os.Stderr.WriteString("Traceback: worker crashed\n")
+			os.Exit(1)
+		case "hang":
+			time.Sleep(time.Minute)
+		default:
+			resp, _ := json.Marshal(workerResponse{ID: req.ID, OK: true, ProcessingTime: 0.5, RealTimeFactor: float64(handled)})
+			writeFrame(os.Stdout, resp)
+			writeFrame(os.Stdout, fakeWav(24000, 1, 2400))
+		}
+	}
+}
+
+// newWorkerTestTTS creates a TTS instance backed by the fake worker.
+func newWorkerTestTTS(t *testing.T, options ...Option) *TTS {
+	t.Helper()
+	opts := append([]Option{
+		WithWorkerCommand(os.Args[0], "-test.run=^TestWorkerHelperProcess$"),
+		WithEnv(fakeWorkerEnv),
+		WithMaxRetries(1),
+	}, options...)
+
+	coqui, _ := newTestTTS(t, opts...)
+	t.Cleanup(func() { coqui.Close() })
+	return coqui
+}
+
+func TestWorker_KeepsProcessBetweenCalls(t *testing.T) {
+	coqui := newWorkerTestTTS(t)
+
+	result, err := coqui.Synthesize("Hello", "first.wav")
+	require.NoError(t, err)
+	assert.Equal(t, 1.0, result.RealTimeFactor)
+	assert.Equal(t, 500*time.Millisecond, result.ProcessingTime)
+	assert.Equal(t, 24000, result.SampleRate)
+	assert.Equal(t, 100*time.Millisecond, result.Duration)
+
+	result, err = coqui.Synthesize("World", "second.wav")
+	require.NoError(t, err)
+	assert.Equal(t, 2.0, result.RealTimeFactor, "The second call should be served by the same worker")
+
+	audio, err := coqui.SynthesizeBytes(context.Background(), "Again")
+	require.NoError(t, err)
+	assert.Equal(t, fakeWav(24000, 1, 2400), audio)
+}
+
+func TestWorker_DoesNotRunCLI(t *testing.T) {
+	coqui := newWorkerTestTTS(t)
+	runner := coqui.CurrentRunner().(*fakeRunner)
+
+	_, err := coqui.Synthesize("Hello", "hello.wav")
+	require.NoError(t, err)
+	assert.Empty(t, runner.calls(), "The worker should be used instead of the CLI")
+}
+
+func TestWorker_ErrorResponse(t *testing.T) {
+	coqui := newWorkerTestTTS(t)
+
+	_, err := coqui.Synthesize("fail", "fail.wav")
+	assert.ErrorIs(t, err, ErrModelNotFound, "Worker errors should be classified like CLI errors")
+
+	// The worker is still usable after a failed request.
+	result, err := coqui.Synthesize("Hello", "hello.wav")
+	require.NoError(t, err)
+	assert.Equal(t, 2.0, result.RealTimeFactor)
+}
+
+func TestWorker_RestartsAfterCrash(t *testing.T) {
+	coqui := newWorkerTestTTS(t)
+
+	_, err := coqui.Synthesize("crash", "crash.wav")
+	require.Error(t, err)
+	var cmdErr *CommandError
+	require.ErrorAs(t, err, &cmdErr)
+	assert.Contains(t, string(cmdErr.Output), "worker crashed", "The worker's stderr should be included")
+
+	result, err := coqui.Synthesize("Hello", "hello.wav")
+	require.NoError(t, err)
+	assert.Equal(t, 1.0, result.RealTimeFactor, "A new worker should have been started")
+}
+
+func TestWorker_ContextCancelled(t *testing.T) {
+	coqui := newWorkerTestTTS(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := coqui.SynthesizeContext(ctx, "hang", "hang.wav")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 10*time.Second)
+
+	_, err = coqui.Synthesize("Hello", "hello.wav")
+	assert.NoError(t, err, "The worker should restart after a cancelled request")
+}
+
+func TestWorker_Close(t *testing.T) {
+	coqui := newWorkerTestTTS(t)
+	_, err := coqui.Synthesize("Hello", "hello.wav")
+	require.NoError(t, err)
+
+	w := coqui.backend.(*worker)
+	require.NotNil(t, w.cmd)
+	require.NoError(t, coqui.Close())
+	assert.Nil(t, w.cmd, "Close should stop the worker")
+	assert.NoError(t, coqui.Close(), "Close should be safe to call twice")
+}
+
+func TestWorker_MissingExecutable(t *testing.T) {
+	coqui, _ := newTestTTS(t, WithWorkerCommand("/does/not/exist"), WithMaxRetries(1))
+	_, err := coqui.Synthesize("Hello", "hello.wav")
+	assert.ErrorIs(t, err, ErrExecutableNotFound)
+}
+
+func TestWorkerRequest(t *testing.T) {
+	sample := writeTargets(t, "speaker.wav")[0]
+	coqui, _ := newTestTTS(t, WithSpeakerSample(sample), WithSpeakerIndex("p225"))
+	req := coqui.workerRequest(7, "Hello")
+
+	assert.Equal(t, workerRequest{
+		ID:         7,
+		Text:       "Hello",
+		ModelName:  coqui.Name(),
+		Device:     "cpu",
+		SpeakerWav: []string{sample},
+		Speaker:    "p225",
+		Language:   "en",
+	}, req)
+}
+
+func TestWorkerRequest_Style(t *testing.T) {
+	coqui, _ := newTestTTS(t, WithTemperature(0.65), WithSpeed(1.2))
+	req := coqui.workerRequest(1, "Hello")
+
+	assert.Equal(t, 0.65, req.Temperature)
+	assert.Equal(t, 1.2, req.Speed)
+}
+
+func TestFrames(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, writeFrame(&buf, []byte("hello")))
+	require.NoError(t, writeFrame(&buf, nil))
+
+	frame, err := readFrame(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("hello"), frame)
+
+	frame, err = readFrame(&buf)
+	require.NoError(t, err)
+	assert.Empty(t, frame)
+
+	_, err = readFrame(&buf)
+	assert.Error(t, err)
+}
+
+func TestTailBuffer(t *testing.T) {
+	b := &tailBuffer{limit: 4}
+	b.Write([]byte("ab"))
+	b.Write([]byte("cdef"))
+	assert.Equal(t, []byte("cdef"), b.Bytes())
+}
+
+func TestWorkerScriptIsBundled(t *testing.T) {
+	assert.Contains(t, string(workerScript), "def main():")
+}